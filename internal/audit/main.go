@@ -0,0 +1,150 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+func Command() *cli.Command {
+	command := cli.Command{
+		Name:  "audit",
+		Usage: "Audit Utilities",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "show",
+				Usage: "Show the local audit log",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "target",
+						Usage: "only show targets whose name matches this pattern",
+					},
+					&cli.StringFlag{
+						Name:  "scope",
+						Usage: "only show targets whose scope matches this pattern",
+					},
+					&cli.StringFlag{
+						Name:  "event",
+						Usage: "only show this event (connect, shell or terminate)",
+					},
+					&cli.StringFlag{
+						Name:  "session",
+						Usage: "only show this session id",
+					},
+					&cli.DurationFlag{
+						Name:  "since",
+						Usage: "only show records newer than this, e.g. 24h",
+					},
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "print records as JSON lines",
+					},
+				},
+				Action: Show,
+			},
+		},
+	}
+
+	return &command
+}
+
+func Show(c *cli.Context) error {
+	filter, err := newFilter(c)
+	if err != nil {
+		return err
+	}
+
+	records, err := Read(filter)
+	if err != nil {
+		return err
+	}
+
+	if c.Bool("json") {
+		encoder := json.NewEncoder(os.Stdout)
+		for _, record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tEVENT\tUSER\tTARGET\tSESSION\tPORT\tDETAILS")
+	for _, record := range records {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s/%s\t%s\t%d\t%s\n",
+			record.Time.Local().Format(time.RFC3339),
+			record.Event,
+			record.LocalUser+"@"+record.Hostname,
+			record.Scope, record.TargetName,
+			record.SessionId,
+			record.Port,
+			details(record),
+		)
+	}
+
+	return w.Flush()
+}
+
+func details(record Record) string {
+	parts := []string{}
+	if len(record.Command) > 0 {
+		parts = append(parts, strings.Join(record.Command, " "))
+	}
+	if record.Start != nil && record.End != nil {
+		parts = append(parts, fmt.Sprintf("duration=%s", record.End.Sub(*record.Start).Round(time.Second)))
+	}
+	if record.ExitStatus != nil {
+		parts = append(parts, fmt.Sprintf("exit=%d", *record.ExitStatus))
+	}
+	if record.Error != "" {
+		parts = append(parts, fmt.Sprintf("error=%q", record.Error))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func newFilter(c *cli.Context) (func(Record) bool, error) {
+	var targetRe, scopeRe *regexp.Regexp
+	var err error
+
+	if pattern := c.String("target"); pattern != "" {
+		if targetRe, err = regexp.Compile("(?i)" + pattern); err != nil {
+			return nil, err
+		}
+	}
+	if pattern := c.String("scope"); pattern != "" {
+		if scopeRe, err = regexp.Compile("(?i)" + pattern); err != nil {
+			return nil, err
+		}
+	}
+
+	event := c.String("event")
+	session := c.String("session")
+	var since time.Time
+	if c.IsSet("since") {
+		since = time.Now().Add(-c.Duration("since"))
+	}
+
+	return func(r Record) bool {
+		switch {
+		case targetRe != nil && !targetRe.MatchString(r.TargetName):
+			return false
+		case scopeRe != nil && !scopeRe.MatchString(r.Scope):
+			return false
+		case event != "" && r.Event != event:
+			return false
+		case session != "" && r.SessionId != session:
+			return false
+		case r.Time.Before(since):
+			return false
+		}
+		return true
+	}, nil
+}
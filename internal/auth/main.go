@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/AndreZiviani/boundary-fuzzy/internal/client"
+	"github.com/AndreZiviani/boundary-fuzzy/internal/config"
 	"github.com/hashicorp/boundary/api"
 	"github.com/hashicorp/boundary/api/authmethods"
 	"github.com/hashicorp/boundary/globals"
@@ -15,6 +16,7 @@ import (
 type Auth struct {
 	boundaryClient *api.Client
 	authClient     *authmethods.Client
+	settings       config.Settings
 }
 
 func Command() *cli.Command {
@@ -28,6 +30,22 @@ func Command() *cli.Command {
 				Value:   false,
 				Aliases: []string{"f"},
 			},
+			&cli.BoolFlag{
+				Name:  "print-token",
+				Usage: "print the token to stdout, e.g. to set it in BOUNDARY_TOKEN with the none keyring",
+			},
+		},
+		Subcommands: []*cli.Command{
+			{
+				Name:   "status",
+				Usage:  "Show the logged in user, auth method, token expiration and keyring",
+				Action: Status,
+			},
+			{
+				Name:   "logout",
+				Usage:  "Delete the token on the controller and remove it from the keyring",
+				Action: Logout,
+			},
 		},
 		Action: func(c *cli.Context) error {
 			auth := &Auth{}
@@ -55,15 +73,29 @@ func (a *Auth) getPrimaryAuthMethodId(ctx context.Context) (string, error) {
 }
 
 func (a *Auth) Execute(c *cli.Context) error {
-	_, err := Login(c.Context, c.Bool("force"))
-	return err
+	token, err := Login(c.Context, c.Bool("force"))
+	if err != nil {
+		return err
+	}
+
+	if c.Bool("print-token") {
+		fmt.Println(token)
+	}
+
+	return nil
 }
 
 func Login(ctx context.Context, force bool) (string, error) {
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return "", err
+	}
+
 	boundaryClient, token, err := client.NewBoundaryClient(ctx)
 	auth := &Auth{
 		boundaryClient: boundaryClient,
 		authClient:     authmethods.NewClient(boundaryClient),
+		settings:       cfg.Settings,
 	}
 
 	if err != nil {
@@ -90,12 +122,12 @@ func Login(ctx context.Context, force bool) (string, error) {
 		}
 		return result.Token, nil
 
-	case strings.HasPrefix(pri, globals.PasswordAuthMethodPrefix):
-		// todo
-		return "", fmt.Errorf("password login is not implemented")
-	case strings.HasPrefix(pri, globals.LdapAuthMethodPrefix):
-		// todo
-		return "", fmt.Errorf("LDAP login is not implemented")
+	case strings.HasPrefix(pri, globals.PasswordAuthMethodPrefix), strings.HasPrefix(pri, globals.LdapAuthMethodPrefix):
+		result, err := auth.PasswordLogin(ctx, pri)
+		if err != nil || result == nil {
+			return "", err
+		}
+		return result.Token, nil
 	}
 
 	return "", fmt.Errorf("unknown auth method type")
@@ -41,7 +41,7 @@ func (a *Auth) OIDCLogin(ctx context.Context, methodId string) (*authtokens.Auth
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		for range time.NewTicker(1500 * time.Millisecond).C {
+		for range time.NewTicker(a.settings.OIDCPollInterval.Duration).C {
 			result, err = a.authClient.Authenticate(ctx, methodId, "token", map[string]any{
 				"token_id": startResp.TokenId,
 			})
@@ -70,6 +70,8 @@ func (a *Auth) OIDCLogin(ctx context.Context, methodId string) (*authtokens.Auth
 		return nil, fmt.Errorf("no response from the server")
 	}
 
-	_ = keyring.SaveTokenToKeyring(result)
+	if err := keyring.SaveTokenToKeyring(result); err != nil {
+		return nil, fmt.Errorf("save token: %w", err)
+	}
 	return result.GetAuthToken()
 }
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/AndreZiviani/boundary-fuzzy/internal/keyring"
+	"github.com/hashicorp/boundary/api"
+	"github.com/hashicorp/boundary/api/authtokens"
+	"golang.org/x/term"
+)
+
+// PasswordLogin authenticates with a login name and password asked on the
+// terminal, it is used by both the password and the LDAP auth methods
+func (a *Auth) PasswordLogin(ctx context.Context, methodId string) (*authtokens.AuthToken, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil, fmt.Errorf("password login requires a terminal")
+	}
+
+	fmt.Print("Login name: ")
+	loginName, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Print("Password: ")
+	password, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := a.authClient.Authenticate(ctx, methodId, "login", map[string]any{
+		"login_name": strings.TrimSpace(loginName),
+		"password":   string(password),
+	})
+	if err != nil {
+		if apiErr := api.AsServerError(err); apiErr != nil {
+			return nil, apiErr
+		}
+		return nil, err
+	}
+
+	if err := keyring.SaveTokenToKeyring(result); err != nil {
+		return nil, fmt.Errorf("save token: %w", err)
+	}
+	return result.GetAuthToken()
+}
@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/AndreZiviani/boundary-fuzzy/internal/client"
+	"github.com/AndreZiviani/boundary-fuzzy/internal/keyring"
+	"github.com/hashicorp/boundary/api"
+	"github.com/hashicorp/boundary/api/authmethods"
+	"github.com/hashicorp/boundary/api/authtokens"
+	"github.com/hashicorp/boundary/api/users"
+	"github.com/urfave/cli/v2"
+)
+
+// Status shows who is logged in, with which auth method and until when
+func Status(c *cli.Context) error {
+	backend, err := keyring.Backend()
+	if err != nil {
+		return err
+	}
+
+	token, err := keyring.GetBoundaryToken()
+	if err != nil {
+		fmt.Printf("Not logged in (keyring: %s): %s\n", backend, err)
+		return cli.Exit("", 1)
+	}
+	if token == nil {
+		fmt.Printf("Not logged in (keyring: %s)\n", backend)
+		return cli.Exit("", 1)
+	}
+
+	boundaryClient, err := client.NewClient()
+	if err != nil {
+		return err
+	}
+	boundaryClient.SetToken(token.Token)
+
+	status := "valid"
+	tokenId, err := keyring.TokenIdFromToken(token.Token)
+	if err != nil {
+		return err
+	}
+
+	result, err := authtokens.NewClient(boundaryClient).Read(c.Context, tokenId)
+	switch {
+	case err == nil:
+		token = result.Item
+	case client.IsUnauthenticated(err) || client.IsNotFound(err):
+		status = "expired or revoked"
+	default:
+		status = fmt.Sprintf("unknown, could not reach the controller: %s", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "User:\t%s\n", userName(c, boundaryClient, token.UserId))
+	fmt.Fprintf(w, "Auth Method:\t%s\n", authMethodName(c, boundaryClient, token.AuthMethodId))
+	fmt.Fprintf(w, "Token:\t%s\n", tokenId)
+	fmt.Fprintf(w, "Expires:\t%s\n", expiration(token.ExpirationTime))
+	fmt.Fprintf(w, "Keyring:\t%s\n", backend)
+	fmt.Fprintf(w, "Status:\t%s\n", status)
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if status != "valid" {
+		return cli.Exit("", 1)
+	}
+
+	return nil
+}
+
+// Logout deletes the token on the controller and removes it from the keyring
+func Logout(c *cli.Context) error {
+	backend, err := keyring.Backend()
+	if err != nil {
+		return err
+	}
+
+	// a keyring that can not be read is not the same as not being logged
+	// in, the token would stay valid
+	token, err := keyring.GetBoundaryToken()
+	if err != nil {
+		return fmt.Errorf("could not read the token from keyring %s: %w", backend, err)
+	}
+	if token == nil {
+		fmt.Println("Not logged in")
+		return nil
+	}
+
+	tokenId, err := keyring.TokenIdFromToken(token.Token)
+	if err != nil {
+		return err
+	}
+
+	boundaryClient, err := client.NewClient()
+	if err != nil {
+		return err
+	}
+	boundaryClient.SetToken(token.Token)
+
+	_, err = authtokens.NewClient(boundaryClient).Delete(c.Context, tokenId)
+	if err != nil && !client.IsUnauthenticated(err) && !client.IsNotFound(err) {
+		return fmt.Errorf("could not delete token %s: %w", tokenId, err)
+	}
+
+	_ = client.ForgetToken(tokenId)
+
+	switch backend {
+	case keyring.BackendEnv:
+		fmt.Printf("Token %s deleted, unset %s to finish logging out\n", tokenId, keyring.EnvToken)
+		return nil
+	case keyring.BackendTokenFile, keyring.BackendStdin:
+		fmt.Printf("Token %s deleted\n", tokenId)
+		return nil
+	}
+
+	if err := keyring.DeleteTokenFromKeyring(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Token %s deleted and removed from keyring %q\n", tokenId, backend)
+	return nil
+}
+
+// userName returns the login name of the user, or its id if we are not
+// allowed to read it
+func userName(c *cli.Context, boundaryClient *api.Client, userId string) string {
+	if userId == "" {
+		return "unknown"
+	}
+
+	result, err := users.NewClient(boundaryClient).Read(c.Context, userId)
+	if err != nil {
+		return userId
+	}
+
+	for _, name := range []string{result.Item.LoginName, result.Item.Name, result.Item.FullName} {
+		if name != "" {
+			return fmt.Sprintf("%s (%s)", name, userId)
+		}
+	}
+
+	return userId
+}
+
+func authMethodName(c *cli.Context, boundaryClient *api.Client, authMethodId string) string {
+	if authMethodId == "" {
+		return "unknown"
+	}
+
+	result, err := authmethods.NewClient(boundaryClient).Read(c.Context, authMethodId)
+	if err != nil {
+		return authMethodId
+	}
+
+	if result.Item.Name == "" {
+		return fmt.Sprintf("%s (%s)", authMethodId, result.Item.Type)
+	}
+	return fmt.Sprintf("%s (%s, %s)", result.Item.Name, authMethodId, result.Item.Type)
+}
+
+func expiration(expires time.Time) string {
+	if expires.IsZero() {
+		return "unknown"
+	}
+
+	remaining := time.Until(expires)
+	if remaining <= 0 {
+		return fmt.Sprintf("%s (expired)", expires.Local().Format(time.RFC3339))
+	}
+
+	return fmt.Sprintf("%s (in %s)", expires.Local().Format(time.RFC3339), remaining.Round(time.Second))
+}
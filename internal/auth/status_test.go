@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"flag"
+	"path"
+	"testing"
+
+	"github.com/AndreZiviani/boundary-fuzzy/internal/keyring"
+	"github.com/urfave/cli/v2"
+)
+
+func TestLogout(t *testing.T) {
+	tests := []struct {
+		name      string
+		tokenFile string
+		wantErr   bool
+	}{
+		// nothing to log out of
+		{name: "not logged in"},
+		// e.g. a wrong passphrase, the token must not be reported as gone
+		{name: "unreadable token", tokenFile: path.Join(t.TempDir(), "missing"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			t.Setenv(keyring.EnvToken, "")
+			t.Setenv(keyring.EnvKeyringType, keyring.NoneKeyring)
+			t.Setenv(keyring.EnvTokenFile, tt.tokenFile)
+
+			err := Logout(cli.NewContext(cli.NewApp(), flag.NewFlagSet("logout", flag.ContinueOnError), nil))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Logout() = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
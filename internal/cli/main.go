@@ -4,8 +4,15 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/AndreZiviani/boundary-fuzzy/internal/audit"
 	"github.com/AndreZiviani/boundary-fuzzy/internal/auth"
+	"github.com/AndreZiviani/boundary-fuzzy/internal/config"
+	"github.com/AndreZiviani/boundary-fuzzy/internal/configcmd"
+	"github.com/AndreZiviani/boundary-fuzzy/internal/keyring"
+	"github.com/AndreZiviani/boundary-fuzzy/internal/keyringcmd"
+	"github.com/AndreZiviani/boundary-fuzzy/internal/recording"
 	"github.com/AndreZiviani/boundary-fuzzy/internal/target"
+	"github.com/hashicorp/boundary/api"
 
 	"github.com/urfave/cli/v2"
 )
@@ -20,8 +27,28 @@ func Run() error {
 		version = "Unknown version, manually compiled from git?"
 	}
 
+	err := newApp().Run(os.Args)
+	if err != nil {
+		fmt.Println(err)
+
+		os.Exit(1)
+	}
+
+	return err
+}
+
+func newApp() *cli.App {
 	flags := []cli.Flag{
 		&cli.BoolFlag{Name: "verbose", Usage: "Log debug messages"},
+		&cli.StringFlag{Name: "token-name", Usage: "Name of the token in the keyring, shared with the boundary CLI", EnvVars: []string{keyring.EnvTokenName}, Value: keyring.DefaultTokenName},
+		&cli.StringFlag{Name: "token-file", Usage: "Read the token from a file instead of the keyring, \"-\" reads it from stdin", EnvVars: []string{keyring.EnvTokenFile}},
+		&cli.StringFlag{Name: "ca-cert", Usage: "PEM-encoded CA certificate to verify the controller certificate", EnvVars: []string{api.EnvBoundaryCACert}},
+		&cli.StringFlag{Name: "client-cert", Usage: "PEM-encoded client certificate for the controller", EnvVars: []string{api.EnvBoundaryClientCert}},
+		&cli.StringFlag{Name: "client-key", Usage: "PEM-encoded private key of the client certificate", EnvVars: []string{api.EnvBoundaryClientKey}},
+		&cli.BoolFlag{Name: "tls-insecure", Usage: "Skip verification of the controller certificate", EnvVars: []string{api.EnvBoundaryTLSInsecure}},
+		&cli.StringFlag{Name: "tls-server-name", Usage: "Name to use as the SNI host when connecting to the controller", EnvVars: []string{api.EnvBoundaryTLSServerName}},
+		&cli.StringFlag{Name: "timeout", Usage: "Timeout of requests to the controller, e.g. \"30s\"", EnvVars: []string{api.EnvBoundaryClientTimeout}},
+		&cli.StringFlag{Name: "proxy", Usage: "URL of the proxy used to reach the controller", EnvVars: []string{config.EnvSettingsPrefix + "PROXY_URL"}},
 	}
 
 	app := &cli.App{
@@ -31,19 +58,28 @@ func Run() error {
 		UsageText:   "boundary-fuzzy [global options] command [command options] [arguments...]",
 		Version:     version,
 		HideVersion: false,
+		// the keyring and the API client read the global options from the
+		// environment
+		Before: func(c *cli.Context) error {
+			for _, flag := range flags {
+				envFlag, ok := flag.(cli.DocGenerationFlag)
+				if !ok || len(envFlag.GetEnvVars()) == 0 || !c.IsSet(flag.Names()[0]) {
+					continue
+				}
+				os.Setenv(envFlag.GetEnvVars()[0], fmt.Sprint(c.Value(flag.Names()[0])))
+			}
+			return nil
+		},
 		Commands: []*cli.Command{
 			target.Command(),
 			auth.Command(),
+			configcmd.Command(),
+			keyringcmd.Command(),
+			audit.Command(),
+			recording.Command(),
 		},
 		EnableBashCompletion: true,
 	}
 
-	err := app.Run(os.Args)
-	if err != nil {
-		fmt.Println(err)
-
-		os.Exit(1)
-	}
-
-	return err
+	return app
 }
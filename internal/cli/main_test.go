@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"os"
+	"testing"
+
+	"github.com/AndreZiviani/boundary-fuzzy/internal/keyring"
+	"github.com/urfave/cli/v2"
+)
+
+func TestTokenNamePrecedence(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		env  string
+		want string
+	}{
+		{name: "default", want: keyring.DefaultTokenName},
+		{name: "env", env: "from-env", want: "from-env"},
+		{name: "flag", args: []string{"--token-name", "from-flag"}, want: "from-flag"},
+		{name: "flag over env", args: []string{"--token-name", "from-flag"}, env: "from-env", want: "from-flag"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// unset by default, the flag sets it for the rest of the process
+			t.Setenv(keyring.EnvTokenName, tt.env)
+			if tt.env == "" {
+				os.Unsetenv(keyring.EnvTokenName)
+			}
+
+			var got string
+			app := newApp()
+			app.Commands = []*cli.Command{{
+				Name:   "probe",
+				Action: func(*cli.Context) error { got = keyring.TokenName(); return nil },
+			}}
+
+			args := append([]string{"boundary-fuzzy"}, tt.args...)
+			if err := app.Run(append(args, "probe")); err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tt.want {
+				t.Errorf("TokenName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
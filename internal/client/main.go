@@ -3,24 +3,43 @@ package client
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"time"
 
+	"github.com/AndreZiviani/boundary-fuzzy/internal/config"
 	"github.com/AndreZiviani/boundary-fuzzy/internal/keyring"
 	"github.com/hashicorp/boundary/api"
 	"github.com/hashicorp/boundary/api/authtokens"
-	"github.com/hashicorp/boundary/api/sessions"
 )
 
-func NewBoundaryClient(ctx context.Context) (*api.Client, *authtokens.AuthToken, error) {
+// NewClient returns a client for the controller in BOUNDARY_ADDR without a token
+func NewClient() (*api.Client, error) {
 	boundaryAddr := os.Getenv("BOUNDARY_ADDR")
 	if boundaryAddr == "" {
-		return nil, nil, fmt.Errorf("environment variable BOUNDARY_ADDR is not set")
+		return nil, fmt.Errorf("environment variable BOUNDARY_ADDR is not set")
+	}
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return nil, err
 	}
 
-	config, _ := api.DefaultConfig()
-	config.Addr = boundaryAddr
-	boundaryClient, err := api.NewClient(config)
+	apiConfig, err := api.DefaultConfig()
+	if err != nil {
+		return nil, err
+	}
+	apiConfig.Addr = boundaryAddr
+
+	if err := applySettings(apiConfig, cfg.Settings); err != nil {
+		return nil, err
+	}
+
+	return api.NewClient(apiConfig)
+}
+
+func NewBoundaryClient(ctx context.Context) (*api.Client, *authtokens.AuthToken, error) {
+	boundaryClient, err := NewClient()
 	if err != nil {
 		return nil, nil, err
 	}
@@ -31,18 +50,51 @@ func NewBoundaryClient(ctx context.Context) (*api.Client, *authtokens.AuthToken,
 		return boundaryClient, nil, err
 	}
 
-	if time.Now().Before(boundaryToken.ExpirationTime) {
-		boundaryClient.SetToken(boundaryToken.Token)
-		sessionsClient := sessions.NewClient(boundaryClient)
-		_, err = sessionsClient.List(ctx, "global", sessions.WithRecursive(true))
-		if err != nil {
-			// token is invalid
-			boundaryClient.SetToken("")
-			boundaryToken = nil
-		}
-	} else {
-		boundaryToken = nil
+	if boundaryToken == nil {
+		return boundaryClient, nil, nil
+	}
+
+	// tokens from the environment do not have an expiration time
+	if !boundaryToken.ExpirationTime.IsZero() && time.Now().After(boundaryToken.ExpirationTime) {
+		return boundaryClient, nil, nil
+	}
+
+	boundaryClient.SetToken(boundaryToken.Token)
+	validated, err := validateToken(ctx, boundaryClient, boundaryToken)
+	switch {
+	case err == nil:
+		return boundaryClient, validated, nil
+
+	case IsUnauthenticated(err) || IsNotFound(err):
+		// token is invalid
+		boundaryClient.SetToken("")
+		return boundaryClient, nil, nil
+
+	case api.AsServerError(err) != nil:
+		// the controller answered, but we could not tell if the token is valid
+		return boundaryClient, boundaryToken, fmt.Errorf("could not validate token: %w", err)
+	}
+
+	// we are probably offline, that does not mean we are logged out
+	return boundaryClient, boundaryToken, fmt.Errorf("%w: %w", ErrUnreachable, err)
+}
+
+// IsUnauthenticated reports if err means the token is missing, expired or
+// was revoked
+func IsUnauthenticated(err error) bool {
+	return statusCode(err) == http.StatusUnauthorized
+}
+
+// IsNotFound reports if err means the resource does not exist
+func IsNotFound(err error) bool {
+	return statusCode(err) == http.StatusNotFound
+}
+
+func statusCode(err error) int {
+	apiErr := api.AsServerError(err)
+	if apiErr == nil || apiErr.Response() == nil {
+		return 0
 	}
 
-	return boundaryClient, boundaryToken, nil
+	return apiErr.Response().StatusCode()
 }
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/AndreZiviani/boundary-fuzzy/internal/config"
+	"github.com/hashicorp/boundary/api"
+)
+
+// applySettings sets the TLS, timeout and proxy options of the client from the
+// settings, the BOUNDARY_* variables already read by api.DefaultConfig take
+// precedence like they do for the boundary CLI
+func applySettings(apiConfig *api.Config, settings config.Settings) error {
+	tlsConfig := *apiConfig.TLSConfig
+	for _, option := range []struct {
+		env     string
+		setting string
+		value   *string
+	}{
+		{api.EnvBoundaryCACert, settings.TLSCACert, &tlsConfig.CACert},
+		{api.EnvBoundaryClientCert, settings.TLSClientCert, &tlsConfig.ClientCert},
+		{api.EnvBoundaryClientKey, settings.TLSClientKey, &tlsConfig.ClientKey},
+		{api.EnvBoundaryTLSServerName, settings.TLSServerName, &tlsConfig.ServerName},
+	} {
+		if option.setting != "" && os.Getenv(option.env) == "" {
+			*option.value = option.setting
+		}
+	}
+	if settings.TLSInsecure && os.Getenv(api.EnvBoundaryTLSInsecure) == "" {
+		tlsConfig.Insecure = true
+	}
+
+	if tlsConfig != *apiConfig.TLSConfig {
+		apiConfig.TLSConfig = &tlsConfig
+		if err := apiConfig.ConfigureTLS(); err != nil {
+			return fmt.Errorf("could not configure TLS: %w", err)
+		}
+	}
+
+	if os.Getenv(api.EnvBoundaryClientTimeout) == "" {
+		apiConfig.Timeout = settings.ClientTimeout.Duration
+	}
+
+	if settings.ProxyURL != "" {
+		proxyURL, err := url.Parse(settings.ProxyURL)
+		if err != nil {
+			return fmt.Errorf("invalid proxy_url: %w", err)
+		}
+		transport, ok := apiConfig.HttpClient.Transport.(*http.Transport)
+		if !ok {
+			return fmt.Errorf("could not set proxy_url, the HTTP transport is a %T", apiConfig.HttpClient.Transport)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/AndreZiviani/boundary-fuzzy/internal/config"
+	"github.com/hashicorp/boundary/api"
+)
+
+// wrappedTransport stands for a transport wrapped e.g. for tracing
+type wrappedTransport struct {
+	http.RoundTripper
+}
+
+func TestApplySettingsProxy(t *testing.T) {
+	settings := config.DefaultSettings()
+	settings.ProxyURL = "http://proxy.example.com:3128"
+
+	apiConfig, err := api.DefaultConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := applySettings(apiConfig, settings); err != nil {
+		t.Fatal(err)
+	}
+
+	request, _ := http.NewRequest(http.MethodGet, "https://boundary.example.com", nil)
+	proxy, err := apiConfig.HttpClient.Transport.(*http.Transport).Proxy(request)
+	if err != nil || proxy == nil || proxy.Host != "proxy.example.com:3128" {
+		t.Fatalf("proxy is %v, %v, want proxy.example.com:3128", proxy, err)
+	}
+
+	apiConfig.HttpClient.Transport = wrappedTransport{apiConfig.HttpClient.Transport}
+	if err := applySettings(apiConfig, settings); err == nil {
+		t.Fatal("expected an error setting the proxy of a wrapped transport")
+	}
+
+	settings.ProxyURL = "://invalid"
+	if err := applySettings(apiConfig, settings); err == nil {
+		t.Fatal("expected an error for an invalid proxy_url")
+	}
+}
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/boundary/api"
+	"github.com/hashicorp/boundary/api/scopes"
+	"github.com/hashicorp/boundary/api/targets"
+)
+
+// ScopePaths returns a map of scope id to a human readable path made of the
+// scope names, e.g. "my-org/my-project"
+func ScopePaths(ctx context.Context, boundaryClient *api.Client) (map[string]string, error) {
+	scopesResult, err := scopes.NewClient(boundaryClient).List(ctx, "global", scopes.WithRecursive(true))
+	if err != nil {
+		return nil, err
+	}
+
+	byId := make(map[string]*scopes.Scope, len(scopesResult.Items))
+	for _, scope := range scopesResult.Items {
+		byId[scope.Id] = scope
+	}
+
+	paths := make(map[string]string, len(byId))
+	for id := range byId {
+		names := []string{}
+		for current, ok := byId[id]; ok; current, ok = byId[current.ScopeId] {
+			names = append([]string{current.Name}, names...)
+		}
+		paths[id] = strings.Join(names, "/")
+	}
+
+	return paths, nil
+}
+
+// TargetScopePath returns the scope path of a target, falling back to the
+// scope name when the path is unknown
+func TargetScopePath(target *targets.Target, paths map[string]string) string {
+	if path, ok := paths[target.ScopeId]; ok {
+		return path
+	}
+
+	if target.Scope != nil {
+		return target.Scope.Name
+	}
+
+	return target.ScopeId
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/AndreZiviani/boundary-fuzzy/internal/config"
+	"github.com/AndreZiviani/boundary-fuzzy/internal/keyring"
+	"github.com/faabiosr/cachego/file"
+	"github.com/hashicorp/boundary/api/targets"
+)
+
+const targetCachePrefix = "targets-"
+
+// TargetCache is the last target list fetched from a controller
+type TargetCache struct {
+	Targets []*targets.Target `json:"targets"`
+	SavedAt time.Time         `json:"saved_at"`
+}
+
+// Age returns how long ago the targets were listed
+func (c *TargetCache) Age() time.Duration {
+	return time.Since(c.SavedAt)
+}
+
+// targetCacheKey identifies the controller and the token name, the same
+// controller may be used with different users
+func targetCacheKey(addr string) string {
+	return targetCachePrefix + addr + "-" + keyring.TokenName()
+}
+
+// CachedTargets returns the targets last listed from the controller, unless
+// they are older than the configured max age
+func CachedTargets(cfg config.Config, addr string) (*TargetCache, bool) {
+	if cfg.Settings.TargetCacheMaxAge.Duration <= 0 {
+		return nil, false
+	}
+
+	configFolder, err := cfg.ConfigFolder()
+	if err != nil {
+		return nil, false
+	}
+
+	cached, err := file.New(configFolder).Fetch(targetCacheKey(addr))
+	if err != nil {
+		return nil, false
+	}
+
+	cache := &TargetCache{}
+	if err := json.Unmarshal([]byte(cached), cache); err != nil {
+		return nil, false
+	}
+
+	return cache, true
+}
+
+// CacheTargets saves the targets listed from the controller until the
+// configured max age, a max age of 0 disables the cache
+func CacheTargets(cfg config.Config, addr string, items []*targets.Target) {
+	maxAge := cfg.Settings.TargetCacheMaxAge.Duration
+	if maxAge <= 0 {
+		return
+	}
+
+	configFolder, err := cfg.ConfigFolder()
+	if err != nil {
+		return
+	}
+
+	value, err := json.Marshal(TargetCache{Targets: items, SavedAt: time.Now()})
+	if err != nil {
+		return
+	}
+
+	// the cache only speeds up the next start, failing to write it is not an error
+	_ = file.New(configFolder).Save(targetCacheKey(addr), string(value), maxAge)
+}
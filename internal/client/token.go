@@ -0,0 +1,116 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AndreZiviani/boundary-fuzzy/internal/config"
+	"github.com/AndreZiviani/boundary-fuzzy/internal/keyring"
+	"github.com/faabiosr/cachego/file"
+	"github.com/hashicorp/boundary/api"
+	"github.com/hashicorp/boundary/api/authtokens"
+)
+
+// ErrUnreachable is returned with the token when it could not be validated
+// because the controller could not be reached, the token may still be valid
+var ErrUnreachable = errors.New("could not reach the controller to validate the token")
+
+const tokenCachePrefix = "token-"
+
+// validateToken reads the token from the controller using its own id, the
+// result is cached for the configured TTL so the next commands start faster
+func validateToken(ctx context.Context, boundaryClient *api.Client, token *authtokens.AuthToken) (*authtokens.AuthToken, error) {
+	tokenId, err := keyring.TokenIdFromToken(token.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if cached, ok := cachedToken(cfg, tokenId); ok {
+		cached.Token = token.Token
+		return cached, nil
+	}
+
+	result, err := authtokens.NewClient(boundaryClient).Read(ctx, tokenId)
+	if err != nil {
+		return nil, err
+	}
+
+	validated := result.Item
+	cacheToken(cfg, validated)
+	validated.Token = token.Token
+
+	return validated, nil
+}
+
+func cachedToken(cfg config.Config, tokenId string) (*authtokens.AuthToken, bool) {
+	configFolder, err := cfg.ConfigFolder()
+	if err != nil {
+		return nil, false
+	}
+
+	cached, err := file.New(configFolder).Fetch(tokenCachePrefix + tokenId)
+	if err != nil {
+		return nil, false
+	}
+
+	token := &authtokens.AuthToken{}
+	if err := json.Unmarshal([]byte(cached), token); err != nil {
+		return nil, false
+	}
+
+	return token, true
+}
+
+// cacheToken saves the token details, without the token itself, until the
+// TTL or the token expires
+func cacheToken(cfg config.Config, token *authtokens.AuthToken) {
+	ttl := cfg.Settings.TokenValidationTTL.Duration
+	if !token.ExpirationTime.IsZero() {
+		ttl = min(ttl, time.Until(token.ExpirationTime))
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	configFolder, err := cfg.ConfigFolder()
+	if err != nil {
+		return
+	}
+
+	details := *token
+	details.Token = ""
+	value, err := json.Marshal(details)
+	if err != nil {
+		return
+	}
+
+	// the cache only saves a request, failing to write it is not an error
+	_ = file.New(configFolder).Save(tokenCachePrefix+token.Id, string(value), ttl)
+}
+
+// ForgetToken removes the cached validation of a token, e.g. after logging out
+func ForgetToken(tokenId string) error {
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return err
+	}
+
+	configFolder, err := cfg.ConfigFolder()
+	if err != nil {
+		return err
+	}
+
+	if err := file.New(configFolder).Delete(tokenCachePrefix + tokenId); err != nil {
+		return fmt.Errorf("could not remove cached token: %w", err)
+	}
+
+	return nil
+}
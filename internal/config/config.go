@@ -9,16 +9,28 @@ import (
 	"github.com/faabiosr/cachego/file"
 )
 
+// AppName names the config folder
+const AppName = "boundary-fuzzy"
+
 type Config struct {
 	AppName   string
 	Favorites []string
+	Settings  Settings
+
+	// sources tracks which layer set each setting
+	sources map[string]string
 }
 
 func NewConfig() (Config, error) {
 	c := Config{
-		AppName: "boundary-fuzzy",
+		AppName: AppName,
 	}
 	err := c.SetupConfigFolder()
+	if err != nil {
+		return c, err
+	}
+
+	err = c.LoadSettings()
 
 	return c, err
 }
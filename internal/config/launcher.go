@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Launcher replaces the client started for targets whose scope, name and
+// default port match. The placeholders {{host}}, {{port}}, {{username}} and
+// {{password}} are replaced in the command, background launchers keep running
+// detached from the terminal for as long as the session is active
+type Launcher struct {
+	Scope      string   `json:"scope"`
+	Target     string   `json:"target"`
+	Port       int      `json:"port"`
+	Command    []string `json:"command"`
+	Background bool     `json:"background"`
+}
+
+// Matches reports if the launcher applies to a target, patterns are case
+// insensitive and a zero port matches any port
+func (l Launcher) Matches(scope, target string, port int) bool {
+	if l.Port != 0 && l.Port != port {
+		return false
+	}
+
+	return Policy{Scope: l.Scope, Target: l.Target}.Matches(scope, target)
+}
+
+// LauncherFor returns the first launcher matching a target
+func (s Settings) LauncherFor(scope, target string, port int) (Launcher, bool) {
+	for _, launcher := range s.Launchers {
+		if launcher.Matches(scope, target, port) {
+			return launcher, true
+		}
+	}
+
+	return Launcher{}, false
+}
+
+func validateLaunchers(launchers []Launcher) error {
+	for i, launcher := range launchers {
+		if len(launcher.Command) == 0 {
+			return fmt.Errorf("missing command in launcher %d", i)
+		}
+
+		for _, pattern := range []string{launcher.Scope, launcher.Target} {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("invalid pattern %q in launcher %d: %w", pattern, i, err)
+			}
+		}
+	}
+
+	return nil
+}
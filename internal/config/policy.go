@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Policy adds guardrails to targets whose scope and name match the patterns,
+// an empty pattern matches everything
+type Policy struct {
+	Scope    string `json:"scope"`
+	Target   string `json:"target"`
+	Confirm  bool   `json:"confirm"`
+	ReadOnly bool   `json:"read_only"`
+	Banner   string `json:"banner"`
+	Audit    bool   `json:"audit"`
+	Record   bool   `json:"record"`
+}
+
+// Matches reports if the policy applies to a target, patterns are case insensitive
+func (p Policy) Matches(scope, target string) bool {
+	for _, match := range [][2]string{{p.Scope, scope}, {p.Target, target}} {
+		if match[0] == "" {
+			continue
+		}
+
+		ok, err := regexp.MatchString("(?i)"+match[0], match[1])
+		if err != nil || !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Guarded reports if the user must be asked before connecting
+func (p Policy) Guarded() bool {
+	return p.Confirm || p.Banner != ""
+}
+
+// PolicyFor merges every policy matching a target
+func (s Settings) PolicyFor(scope, target string) Policy {
+	merged := Policy{}
+	banners := []string{}
+	for _, policy := range s.Policies {
+		if !policy.Matches(scope, target) {
+			continue
+		}
+
+		merged.Confirm = merged.Confirm || policy.Confirm
+		merged.ReadOnly = merged.ReadOnly || policy.ReadOnly
+		merged.Audit = merged.Audit || policy.Audit
+		merged.Record = merged.Record || policy.Record
+		if policy.Banner != "" {
+			banners = append(banners, policy.Banner)
+		}
+	}
+	merged.Banner = strings.Join(banners, "\n")
+
+	return merged
+}
+
+func validatePolicies(policies []Policy) error {
+	for i, policy := range policies {
+		for _, pattern := range []string{policy.Scope, policy.Target} {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("invalid pattern %q in policy %d: %w", pattern, i, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,330 @@
+package config
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	SettingsFileName        = "config.json"
+	SystemSettingsFolder    = "/etc/boundary-fuzzy"
+	EnvSettingsPrefix       = "BOUNDARY_FUZZY_"
+	DefaultSettingsSource   = "default"
+	envSettingsSourcePrefix = "env "
+)
+
+// Settings holds every value that can be overridden by the system config file,
+// the user config file or environment variables, in this order of precedence
+type Settings struct {
+	OIDCPollInterval                   Duration                       `json:"oidc_poll_interval"`
+	TokenExpiryWarning                 Duration                       `json:"token_expiry_warning"`
+	TokenValidationTTL                 Duration                       `json:"token_validation_ttl"`
+	TLSCACert                          string                         `json:"tls_ca_cert"`
+	TLSClientCert                      string                         `json:"tls_client_cert"`
+	TLSClientKey                       string                         `json:"tls_client_key"`
+	TLSInsecure                        bool                           `json:"tls_insecure"`
+	TLSServerName                      string                         `json:"tls_server_name"`
+	ClientTimeout                      Duration                       `json:"client_timeout"`
+	ProxyURL                           string                         `json:"proxy_url"`
+	TargetCacheMaxAge                  Duration                       `json:"target_cache_max_age"`
+	ProxyListenTimeout                 Duration                       `json:"proxy_listen_timeout"`
+	ProxyListenAddress                 string                         `json:"proxy_listen_address"`
+	PostgresDatabase                   string                         `json:"postgres_database"`
+	MySQLDatabase                      string                         `json:"mysql_database"`
+	ClickHouseAcceptInvalidCertificate bool                           `json:"clickhouse_accept_invalid_certificate"`
+	Theme                              string                         `json:"theme"`
+	ThemeFile                          string                         `json:"theme_file"`
+	EnvironmentColors                  map[string]string              `json:"environment_colors"`
+	KubernetesCommand                  []string                       `json:"kubernetes_command"`
+	KubernetesCAFile                   string                         `json:"kubernetes_ca_file"`
+	KubernetesUser                     string                         `json:"kubernetes_user"`
+	HTTPRewriteHost                    bool                           `json:"http_rewrite_host"`
+	ReauthorizeOnConnectionLimit       bool                           `json:"reauthorize_on_connection_limit"`
+	AuditLog                           bool                           `json:"audit_log"`
+	RecordSessions                     bool                           `json:"record_sessions"`
+	RecordingRetention                 Duration                       `json:"recording_retention"`
+	Policies                           []Policy                       `json:"policies"`
+	Launchers                          []Launcher                     `json:"launchers"`
+	KeyPreset                          string                         `json:"key_preset"`
+	KeyBindings                        map[string]map[string][]string `json:"key_bindings"`
+}
+
+func DefaultSettings() Settings {
+	return Settings{
+		OIDCPollInterval:                   Duration{1500 * time.Millisecond},
+		TokenExpiryWarning:                 Duration{10 * time.Minute},
+		TokenValidationTTL:                 Duration{5 * time.Minute},
+		TLSInsecure:                        false,
+		ClientTimeout:                      Duration{60 * time.Second},
+		TargetCacheMaxAge:                  Duration{7 * 24 * time.Hour},
+		ProxyListenTimeout:                 Duration{5 * time.Second},
+		ProxyListenAddress:                 "127.0.0.1",
+		PostgresDatabase:                   "postgres",
+		MySQLDatabase:                      "information_schema",
+		ClickHouseAcceptInvalidCertificate: true,
+		Theme:                              "auto",
+		EnvironmentColors:                  map[string]string{"prod": "#cc0000"},
+		KubernetesCommand:                  []string{},
+		ReauthorizeOnConnectionLimit:       false,
+		AuditLog:                           true,
+		RecordSessions:                     false,
+		RecordingRetention:                 Duration{30 * 24 * time.Hour},
+		Policies:                           []Policy{},
+		Launchers:                          []Launcher{},
+		KeyPreset:                          "default",
+		KeyBindings:                        map[string]map[string][]string{},
+	}
+}
+
+// Duration is a time.Duration that is read and written as a string, e.g. "1500ms"
+type Duration struct {
+	time.Duration
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	duration, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	d.Duration = duration
+	return nil
+}
+
+// Setting is a single effective setting and where its value came from
+type Setting struct {
+	Name   string
+	Value  string
+	Source string
+}
+
+// SettingsFile returns the path of the user settings file
+func (c Config) SettingsFile() (string, error) {
+	configFolder, err := c.ConfigFolder()
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join(configFolder, SettingsFileName), nil
+}
+
+// LoadSettings loads the defaults, the system and user settings files and the
+// environment variables, each one overriding the previous
+func (c *Config) LoadSettings() error {
+	c.Settings = DefaultSettings()
+	c.sources = map[string]string{}
+
+	userFile, err := c.SettingsFile()
+	if err != nil {
+		return err
+	}
+
+	for _, file := range []string{path.Join(SystemSettingsFolder, SettingsFileName), userFile} {
+		if err := c.loadSettingsFile(file); err != nil {
+			return err
+		}
+	}
+
+	if err := c.loadSettingsEnv(); err != nil {
+		return err
+	}
+
+	if err := validateDurations(c.Settings, c.source); err != nil {
+		return err
+	}
+
+	if err := validatePolicies(c.Settings.Policies); err != nil {
+		return err
+	}
+
+	return validateLaunchers(c.Settings.Launchers)
+}
+
+func (c *Config) loadSettingsFile(file string) error {
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return fmt.Errorf("could not parse %s: %w", file, err)
+	}
+
+	known := settingNames()
+	for key := range keys {
+		if _, ok := known[key]; !ok {
+			return fmt.Errorf("unknown setting %q in %s", key, file)
+		}
+		c.sources[key] = file
+	}
+
+	if err := json.Unmarshal(data, &c.Settings); err != nil {
+		return fmt.Errorf("could not parse %s: %w", file, err)
+	}
+
+	return nil
+}
+
+func (c *Config) loadSettingsEnv() error {
+	settings := reflect.ValueOf(&c.Settings).Elem()
+	for name, i := range settingNames() {
+		env := EnvSettingsPrefix + strings.ToUpper(name)
+		value, ok := os.LookupEnv(env)
+		if !ok {
+			continue
+		}
+
+		field := settings.Field(i)
+		var err error
+		switch v := field.Addr().Interface().(type) {
+		case *string:
+			*v = value
+		case encoding.TextUnmarshaler:
+			err = v.UnmarshalText([]byte(value))
+		default:
+			err = json.Unmarshal([]byte(value), v)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %w", env, err)
+		}
+
+		c.sources[name] = envSettingsSourcePrefix + env
+	}
+
+	return nil
+}
+
+// EffectiveSettings returns every setting with its current value and source
+func (c Config) EffectiveSettings() []Setting {
+	settings := reflect.ValueOf(c.Settings)
+	settingsType := settings.Type()
+
+	effective := make([]Setting, 0, settingsType.NumField())
+	for i := 0; i < settingsType.NumField(); i++ {
+		name := settingsType.Field(i).Tag.Get("json")
+
+		value, _ := json.Marshal(settings.Field(i).Interface())
+		effective = append(effective, Setting{Name: name, Value: string(value), Source: c.source(name)})
+	}
+
+	return effective
+}
+
+// source returns where a setting was set
+func (c Config) source(name string) string {
+	if source, ok := c.sources[name]; ok {
+		return source
+	}
+
+	return DefaultSettingsSource
+}
+
+// positiveDurations must be greater than zero, the other durations can be
+// zero to disable what they control
+var positiveDurations = map[string]bool{
+	"oidc_poll_interval":   true,
+	"proxy_listen_timeout": true,
+}
+
+// validateDurations rejects negative durations, and zero for the durations
+// that must be positive
+func validateDurations(settings Settings, source func(name string) string) error {
+	values := reflect.ValueOf(settings)
+	for i := 0; i < values.NumField(); i++ {
+		duration, ok := values.Field(i).Interface().(Duration)
+		if !ok {
+			continue
+		}
+
+		name := values.Type().Field(i).Tag.Get("json")
+		switch {
+		case duration.Duration < 0:
+			return fmt.Errorf("invalid %s in %s: %s must not be negative", name, source(name), duration)
+		case duration.Duration == 0 && positiveDurations[name]:
+			return fmt.Errorf("invalid %s in %s: must be greater than zero", name, source(name))
+		}
+	}
+
+	return nil
+}
+
+// settingNames maps the json name of every setting to its field index
+func settingNames() map[string]int {
+	settingsType := reflect.TypeOf(Settings{})
+	names := make(map[string]int, settingsType.NumField())
+	for i := 0; i < settingsType.NumField(); i++ {
+		names[settingsType.Field(i).Tag.Get("json")] = i
+	}
+	return names
+}
+
+// UserSettings returns the raw settings set in the user settings file
+func (c Config) UserSettings() (map[string]json.RawMessage, error) {
+	file, err := c.SettingsFile()
+	if err != nil {
+		return nil, err
+	}
+
+	settings := map[string]json.RawMessage{}
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return settings, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", file, err)
+	}
+
+	return settings, nil
+}
+
+// SaveUserSettings validates and writes the raw settings to the user settings file
+func (c Config) SaveUserSettings(settings map[string]json.RawMessage) error {
+	file, err := c.SettingsFile()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// make sure the file can be loaded back before writing it
+	known := settingNames()
+	for key := range settings {
+		if _, ok := known[key]; !ok {
+			return fmt.Errorf("unknown setting %q", key)
+		}
+	}
+	validation := DefaultSettings()
+	if err := json.Unmarshal(data, &validation); err != nil {
+		return err
+	}
+	if err := validateDurations(validation, func(string) string { return file }); err != nil {
+		return err
+	}
+	if err := validatePolicies(validation.Policies); err != nil {
+		return err
+	}
+	if err := validateLaunchers(validation.Launchers); err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, data, 0600)
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLoadSettingsDurations(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		env     map[string]string
+		wantErr string
+		// source is where the error says the setting was set
+		source string
+	}{
+		{name: "defaults"},
+		{name: "zero disables", file: `{"target_cache_max_age": "0s", "token_validation_ttl": "0s"}`},
+		{name: "zero poll interval in file", file: `{"oidc_poll_interval": "0s"}`, wantErr: "invalid oidc_poll_interval in ", source: SettingsFileName},
+		{name: "zero listen timeout in file", file: `{"proxy_listen_timeout": "0s"}`, wantErr: "invalid proxy_listen_timeout in ", source: SettingsFileName},
+		{name: "negative in file", file: `{"target_cache_max_age": "-1h"}`, wantErr: "invalid target_cache_max_age in ", source: SettingsFileName},
+		{
+			name:    "negative poll interval in env",
+			env:     map[string]string{"BOUNDARY_FUZZY_OIDC_POLL_INTERVAL": "-1s"},
+			wantErr: "invalid oidc_poll_interval in ",
+			source:  "BOUNDARY_FUZZY_OIDC_POLL_INTERVAL",
+		},
+		{
+			name: "env fixes the file",
+			file: `{"oidc_poll_interval": "0s"}`,
+			env:  map[string]string{"BOUNDARY_FUZZY_OIDC_POLL_INTERVAL": "1s"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			c := Config{AppName: AppName}
+			if err := c.SetupConfigFolder(); err != nil {
+				t.Fatal(err)
+			}
+			if tt.file != "" {
+				file, _ := c.SettingsFile()
+				if err := os.WriteFile(file, []byte(tt.file), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := c.LoadSettings()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) || !strings.Contains(err.Error(), tt.source) {
+				t.Fatalf("LoadSettings() = %v, want an error starting with %q naming %q", err, tt.wantErr, tt.source)
+			}
+		})
+	}
+}
+
+// TestSaveUserSettingsRejectsInvalid checks that settings LoadSettings would
+// reject are never written
+func TestSaveUserSettingsRejectsInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		value string
+	}{
+		{name: "zero poll interval", key: "oidc_poll_interval", value: `"0s"`},
+		{name: "policy pattern", key: "policies", value: `[{"scope": "prod(", "confirm": true}]`},
+		{name: "launcher without command", key: "launchers", value: `[{"target": "grafana", "background": true}]`},
+		{name: "launcher pattern", key: "launchers", value: `[{"target": "grafana(", "command": ["open", "{{host}}"]}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+
+			c := Config{AppName: AppName}
+			if err := c.SetupConfigFolder(); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := c.SaveUserSettings(map[string]json.RawMessage{tt.key: json.RawMessage(tt.value)}); err == nil {
+				t.Fatalf("expected an error saving %s %s", tt.key, tt.value)
+			}
+
+			file, _ := c.SettingsFile()
+			if _, err := os.Stat(file); !os.IsNotExist(err) {
+				t.Fatalf("the settings file was written: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	SharedConfigVersion  = 1
+	SharedConfigFileName = "boundary-fuzzy.json"
+)
+
+// SharedTarget identifies a target by name and scope path so it can be
+// matched on other machines even if its ID changed
+type SharedTarget struct {
+	Name  string `json:"name"`
+	Scope string `json:"scope"`
+	Id    string `json:"id,omitempty"`
+}
+
+func (s SharedTarget) String() string {
+	return fmt.Sprintf("%s/%s", s.Scope, s.Name)
+}
+
+// SharedConfig is the portable file written by "config export" and read by
+// "config import"
+type SharedConfig struct {
+	Version   int                        `json:"version"`
+	Favorites []SharedTarget             `json:"favorites"`
+	Settings  map[string]json.RawMessage `json:"settings,omitempty"`
+}
+
+// LoadSharedConfig reads a shared config from a http(s) URL, a file or a
+// directory (e.g. a git checkout) containing a SharedConfigFileName file
+func LoadSharedConfig(location string) (*SharedConfig, error) {
+	var data []byte
+	var err error
+
+	switch {
+	case location == "-":
+		data, err = io.ReadAll(os.Stdin)
+
+	case strings.HasPrefix(location, "http://"), strings.HasPrefix(location, "https://"):
+		data, err = fetchSharedConfig(location)
+
+	default:
+		if info, statErr := os.Stat(location); statErr == nil && info.IsDir() {
+			location = path.Join(location, SharedConfigFileName)
+		}
+		data, err = os.ReadFile(location)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	shared := &SharedConfig{}
+	if err := json.Unmarshal(data, shared); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", location, err)
+	}
+
+	if shared.Version > SharedConfigVersion {
+		return nil, fmt.Errorf("unsupported shared config version %d", shared.Version)
+	}
+
+	return shared, nil
+}
+
+func fetchSharedConfig(url string) ([]byte, error) {
+	client := http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not fetch %s: %s", url, resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// Write writes the shared config to w
+func (s *SharedConfig) Write(w io.Writer) error {
+	s.Version = SharedConfigVersion
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
@@ -0,0 +1,294 @@
+package configcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/AndreZiviani/boundary-fuzzy/internal/client"
+	"github.com/AndreZiviani/boundary-fuzzy/internal/config"
+	"github.com/hashicorp/boundary/api/targets"
+	"github.com/urfave/cli/v2"
+)
+
+func Command() *cli.Command {
+	command := cli.Command{
+		Name:  "config",
+		Usage: "Config Utilities",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "show",
+				Usage:  "Show the effective settings and where they came from",
+				Action: Show,
+			},
+			{
+				Name:  "export",
+				Usage: "Export favorites and settings to a portable file",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "output",
+						Usage:   "file to write to, defaults to stdout",
+						Aliases: []string{"o"},
+					},
+				},
+				Action: Export,
+			},
+			{
+				Name:      "import",
+				Usage:     "Import favorites and settings from a file, directory or URL",
+				ArgsUsage: "<file|directory|url>",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "replace",
+						Usage: "replace current favorites and settings instead of merging",
+					},
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "only show the changes that would be made",
+					},
+				},
+				Action: Import,
+			},
+		},
+	}
+
+	return &command
+}
+
+// targetIndex resolves targets by id and by scope path and name
+type targetIndex struct {
+	byId   map[string]config.SharedTarget
+	byName map[string]config.SharedTarget
+}
+
+func newTargetIndex(c *cli.Context) (*targetIndex, error) {
+	boundaryClient, _, err := client.NewBoundaryClient(c.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	targetsResult, err := targets.NewClient(boundaryClient).List(c.Context, "global", targets.WithRecursive(true))
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := client.ScopePaths(c.Context, boundaryClient)
+	if err != nil {
+		// we may not be allowed to list scopes, fallback to the scope name
+		paths = map[string]string{}
+	}
+
+	index := &targetIndex{
+		byId:   make(map[string]config.SharedTarget, len(targetsResult.Items)),
+		byName: make(map[string]config.SharedTarget, len(targetsResult.Items)),
+	}
+	for _, target := range targetsResult.Items {
+		shared := config.SharedTarget{
+			Name:  target.Name,
+			Scope: client.TargetScopePath(target, paths),
+			Id:    target.Id,
+		}
+		index.byId[target.Id] = shared
+		index.byName[shared.String()] = shared
+	}
+
+	return index, nil
+}
+
+// resolve finds the current target matching a shared target, by scope path
+// and name first and by id as a fallback
+func (i *targetIndex) resolve(shared config.SharedTarget) (config.SharedTarget, bool) {
+	if target, ok := i.byName[shared.String()]; ok {
+		return target, true
+	}
+
+	target, ok := i.byId[shared.Id]
+	return target, ok
+}
+
+func Export(c *cli.Context) error {
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return err
+	}
+
+	if err := cfg.LoadFavorites(); err != nil {
+		return err
+	}
+
+	index, err := newTargetIndex(c)
+	if err != nil {
+		return err
+	}
+
+	shared := &config.SharedConfig{
+		Favorites: make([]config.SharedTarget, 0, len(cfg.Favorites)),
+	}
+	for _, id := range cfg.Favorites {
+		target, ok := index.byId[id]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "skipping favorite %q, target not found\n", id)
+			continue
+		}
+		shared.Favorites = append(shared.Favorites, target)
+	}
+
+	shared.Settings, err = cfg.UserSettings()
+	if err != nil {
+		return err
+	}
+
+	output := os.Stdout
+	if path := c.String("output"); path != "" {
+		output, err = os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer output.Close()
+	}
+
+	return shared.Write(output)
+}
+
+func Import(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("expected exactly one file, directory or URL to import from")
+	}
+
+	shared, err := config.LoadSharedConfig(c.Args().First())
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return err
+	}
+
+	if err := cfg.LoadFavorites(); err != nil {
+		return err
+	}
+
+	index, err := newTargetIndex(c)
+	if err != nil {
+		return err
+	}
+
+	favorites := []string{}
+	if !c.Bool("replace") {
+		favorites = append(favorites, cfg.Favorites...)
+	}
+
+	for _, favorite := range shared.Favorites {
+		target, ok := index.resolve(favorite)
+		if !ok {
+			fmt.Printf("? %s (not found)\n", favorite)
+			continue
+		}
+
+		if slices.Contains(favorites, target.Id) {
+			continue
+		}
+		favorites = append(favorites, target.Id)
+	}
+
+	settings, err := cfg.UserSettings()
+	if err != nil {
+		return err
+	}
+
+	newSettings := map[string]json.RawMessage{}
+	if !c.Bool("replace") {
+		maps.Copy(newSettings, settings)
+	}
+	maps.Copy(newSettings, shared.Settings)
+
+	printChanges(cfg.Favorites, favorites, index)
+	settingsChanged := printSettingsChanges(settings, newSettings)
+	if c.Bool("dry-run") {
+		return nil
+	}
+
+	if settingsChanged {
+		if err := cfg.SaveUserSettings(newSettings); err != nil {
+			return err
+		}
+	}
+
+	if slices.Equal(cfg.Favorites, favorites) {
+		return nil
+	}
+
+	cfg.Favorites = favorites
+	return cfg.SaveFavorites()
+}
+
+func Show(c *cli.Context) error {
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SETTING\tVALUE\tSOURCE")
+	for _, setting := range cfg.EffectiveSettings() {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", setting.Name, setting.Value, setting.Source)
+	}
+
+	return w.Flush()
+}
+
+// printSettingsChanges prints the settings added, changed and removed and
+// reports if there was any change
+func printSettingsChanges(before, after map[string]json.RawMessage) bool {
+	changed := false
+	for _, name := range slices.Sorted(maps.Keys(before)) {
+		if _, ok := after[name]; !ok {
+			fmt.Printf("- setting %s\n", name)
+			changed = true
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(after)) {
+		value, ok := before[name]
+		switch {
+		case !ok:
+			fmt.Printf("+ setting %s: %s\n", name, after[name])
+			changed = true
+		case !bytes.Equal(value, after[name]):
+			fmt.Printf("~ setting %s: %s -> %s\n", name, value, after[name])
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// printChanges prints the favorites added and removed
+func printChanges(before, after []string, index *targetIndex) {
+	name := func(id string) string {
+		if target, ok := index.byId[id]; ok {
+			return target.String()
+		}
+		return id
+	}
+
+	if slices.Equal(before, after) {
+		fmt.Println("favorites are up to date")
+		return
+	}
+
+	for _, id := range before {
+		if !slices.Contains(after, id) {
+			fmt.Printf("- %s\n", name(id))
+		}
+	}
+	for _, id := range after {
+		if !slices.Contains(before, id) {
+			fmt.Printf("+ %s\n", name(id))
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package keyring
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/AndreZiviani/boundary-fuzzy/internal/config"
+	"github.com/hashicorp/boundary/api/authtokens"
+	nkeyring "github.com/jefferai/keyring"
+	"golang.org/x/term"
+)
+
+// memoryToken holds the token when the keyring type is none, it only lives
+// as long as the process
+var memoryToken struct {
+	sync.Mutex
+	token *authtokens.AuthToken
+}
+
+// KeyringTypes are the keyring types that can be set in BOUNDARY_KEYRING_TYPE,
+// besides auto
+var KeyringTypes = []string{
+	WincredKeyring,
+	KeychainKeyring,
+	SecretServiceKeyring,
+	PassKeyring,
+	FileKeyring,
+	NoneKeyring,
+}
+
+// BackendStatus reports if a keyring type can be used on this machine, Err
+// explains why it can not
+type BackendStatus struct {
+	Name   string
+	Detail string
+	Err    error
+}
+
+// Backends checks every keyring type
+func Backends() []BackendStatus {
+	backends := make([]BackendStatus, 0, len(KeyringTypes))
+	for _, keyringType := range KeyringTypes {
+		backends = append(backends, BackendStatus{
+			Name:   keyringType,
+			Detail: keyringDetail(keyringType),
+			Err:    checkKeyringType(keyringType),
+		})
+	}
+
+	return backends
+}
+
+// autoKeyringType picks the OS keyring on windows and macOS, on other
+// platforms the first usable of pass, secret-service and file
+func autoKeyringType() (string, error) {
+	switch runtime.GOOS {
+	case "windows":
+		return WincredKeyring, nil
+	case "darwin":
+		return KeychainKeyring, nil
+	}
+
+	errs := []error{}
+	for _, keyringType := range []string{PassKeyring, SecretServiceKeyring, FileKeyring} {
+		err := checkKeyringType(keyringType)
+		if err == nil {
+			return keyringType, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", keyringType, err))
+	}
+
+	return "", fmt.Errorf("no keyring is available on this machine, set %s to %q to keep the token in memory only: %w", EnvKeyringType, NoneKeyring, errors.Join(errs...))
+}
+
+// checkKeyringType returns why a keyring type can not be used, or nil
+func checkKeyringType(keyringType string) error {
+	switch keyringType {
+	case NoneKeyring:
+		return nil
+
+	case WincredKeyring:
+		if runtime.GOOS != "windows" {
+			return fmt.Errorf("only available on windows")
+		}
+
+	case KeychainKeyring:
+		if runtime.GOOS != "darwin" {
+			return fmt.Errorf("only available on macOS")
+		}
+
+	case SecretServiceKeyring:
+		if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+			return fmt.Errorf("not available on %s", runtime.GOOS)
+		}
+		if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+			return fmt.Errorf("no D-Bus session bus, DBUS_SESSION_BUS_ADDRESS is not set")
+		}
+		if _, err := openKeyring(SecretServiceKeyring); err != nil {
+			return fmt.Errorf("could not connect to the secret service, is gnome-keyring or kwallet running?")
+		}
+
+	case PassKeyring:
+		if _, err := exec.LookPath("pass"); err != nil {
+			return fmt.Errorf("the pass program is not installed")
+		}
+		if _, err := exec.LookPath("gpg"); err != nil {
+			return fmt.Errorf("the gpg program is not installed")
+		}
+		if _, err := os.Stat(filepath.Join(passDir(), ".gpg-id")); err != nil {
+			return fmt.Errorf("the password store %s is not initialized, run \"pass init <gpg-id>\"", passDir())
+		}
+
+	case FileKeyring:
+		if _, err := fileKeyringDir(); err != nil {
+			return err
+		}
+		if os.Getenv(EnvKeyringPassword) == "" && !term.IsTerminal(int(os.Stdin.Fd())) {
+			return fmt.Errorf("%s is not set and there is no terminal to ask for the passphrase", EnvKeyringPassword)
+		}
+
+	default:
+		return fmt.Errorf("unknown keyring type")
+	}
+
+	return nil
+}
+
+// keyringDetail describes where a keyring type stores the token
+func keyringDetail(keyringType string) string {
+	switch keyringType {
+	case NoneKeyring:
+		return fmt.Sprintf("token kept in memory or read from %s", EnvToken)
+	case WincredKeyring:
+		return "windows credential manager"
+	case KeychainKeyring:
+		return "macOS keychain"
+	case SecretServiceKeyring:
+		return fmt.Sprintf("collection %q", LoginCollection)
+	case PassKeyring:
+		return passDir()
+	case FileKeyring:
+		dir, err := fileKeyringDir()
+		if err != nil {
+			return ""
+		}
+		if os.Getenv(EnvKeyringPassword) != "" {
+			return fmt.Sprintf("%s, passphrase from %s", dir, EnvKeyringPassword)
+		}
+		return fmt.Sprintf("%s, passphrase prompted", dir)
+	}
+
+	return ""
+}
+
+func passDir() string {
+	if dir, ok := os.LookupEnv("PASSWORD_STORE_DIR"); ok {
+		return dir
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "~/.password-store"
+	}
+
+	return filepath.Join(home, ".password-store")
+}
+
+// fileKeyringDir is where the file keyring stores the encrypted token
+func fileKeyringDir() (string, error) {
+	configFolder, err := config.Config{AppName: config.AppName}.ConfigFolder()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configFolder, "keyring"), nil
+}
+
+// filePassword reads the file keyring passphrase from the environment or
+// asks for it in the terminal
+func filePassword(prompt string) (string, error) {
+	if password := os.Getenv(EnvKeyringPassword); password != "" {
+		return password, nil
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("%s is not set and there is no terminal to ask for the passphrase", EnvKeyringPassword)
+	}
+
+	return nkeyring.TerminalPrompt(prompt)
+}
+
+func openKeyring(keyringType string) (nkeyring.Keyring, error) {
+	krConfig := nkeyring.Config{
+		LibSecretCollectionName: LoginCollection,
+		PassPrefix:              PassPrefix,
+		FilePasswordFunc:        filePassword,
+		AllowedBackends:         []nkeyring.BackendType{nkeyring.BackendType(keyringType)},
+	}
+
+	if keyringType == FileKeyring {
+		dir, err := fileKeyringDir()
+		if err != nil {
+			return nil, err
+		}
+		krConfig.FileDir = dir
+	}
+
+	return nkeyring.Open(krConfig)
+}
@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"runtime"
 	"strings"
 
 	"github.com/hashicorp/boundary/api/authmethods"
@@ -22,6 +21,7 @@ const (
 	PassKeyring          = "pass"
 	KeychainKeyring      = "keychain"
 	SecretServiceKeyring = "secret-service"
+	FileKeyring          = "file"
 
 	DefaultTokenName = "default"
 	LoginCollection  = "login"
@@ -30,10 +30,18 @@ const (
 	EnvToken        = "BOUNDARY_TOKEN"
 	EnvTokenName    = "BOUNDARY_TOKEN_NAME"
 	EnvKeyringType  = "BOUNDARY_KEYRING_TYPE"
+	EnvTokenFile    = "BOUNDARY_TOKEN_FILE"
 	StoredTokenName = "HashiCorp Boundary Auth Token"
+
+	// EnvKeyringPassword unlocks the file keyring without prompting
+	EnvKeyringPassword = "BOUNDARY_FUZZY_KEYRING_PASSWORD"
 )
 
 func GetBoundaryToken() (*authtokens.AuthToken, error) {
+	if path := os.Getenv(EnvTokenFile); path != "" && os.Getenv(EnvToken) == "" {
+		return readTokenFile(path)
+	}
+
 	token := os.Getenv(EnvToken)
 	if len(token) == 0 {
 		keyringType, tokenName, err := discoverKeyringTokenInfo()
@@ -52,64 +60,41 @@ func GetBoundaryToken() (*authtokens.AuthToken, error) {
 	}
 }
 
+// TokenName returns the name of the token in the keyring
+func TokenName() string {
+	if tokenName := os.Getenv(EnvTokenName); tokenName != "" {
+		return tokenName
+	}
+
+	return DefaultTokenName
+}
+
 func discoverKeyringTokenInfo() (string, string, error) {
 	// Stops the underlying library from invoking a dbus call that ends up
 	// freezing some systems
 	os.Setenv("DISABLE_KWALLET", "1")
 
-	tokenName := DefaultTokenName
+	tokenName := TokenName()
 
 	// Set so we can look it up later when printing out curl strings
 	os.Setenv(EnvTokenName, tokenName)
 
-	var foundKeyringType bool
 	keyringType := os.Getenv(EnvKeyringType)
-	switch runtime.GOOS {
-	case "windows":
-		switch keyringType {
-		case AutoKeyring, WincredKeyring, PassKeyring:
-			foundKeyringType = true
-			if keyringType == AutoKeyring {
-				keyringType = WincredKeyring
-			}
-		}
-	case "darwin":
-		switch keyringType {
-		case AutoKeyring, KeychainKeyring, PassKeyring:
-			foundKeyringType = true
-			if keyringType == AutoKeyring {
-				keyringType = KeychainKeyring
-			}
-		}
-	default:
-		switch keyringType {
-		case AutoKeyring, SecretServiceKeyring, PassKeyring:
-			foundKeyringType = true
-			if keyringType == AutoKeyring {
-				keyringType = PassKeyring
-			}
+	switch keyringType {
+	case "", AutoKeyring:
+		var err error
+		keyringType, err = autoKeyringType()
+		if err != nil {
+			return "", "", err
 		}
-	}
 
-	if !foundKeyringType {
-		return "", "", fmt.Errorf("given keyring type %q is not valid, or not valid for this platform", EnvKeyringType)
-	}
-
-	var available bool
-	switch keyringType {
-	case WincredKeyring, KeychainKeyring:
-		available = true
-	case PassKeyring, SecretServiceKeyring:
-		avail := nkeyring.AvailableBackends()
-		for _, a := range avail {
-			if keyringType == string(a) {
-				available = true
-			}
+	case NoneKeyring, WincredKeyring, KeychainKeyring, SecretServiceKeyring, PassKeyring, FileKeyring:
+		if err := checkKeyringType(keyringType); err != nil {
+			return "", "", fmt.Errorf("keyring type %q is not available on this machine, run \"keyring doctor\" for details: %w", keyringType, err)
 		}
-	}
 
-	if !available {
-		return "", "", fmt.Errorf("keyring type %q is not available on this machine. For help with setting up keyrings, see https://www.boundaryproject.io/docs/api-clients/cli", keyringType)
+	default:
+		return "", "", fmt.Errorf("given keyring type %q is not valid", keyringType)
 	}
 
 	os.Setenv(EnvKeyringType, keyringType)
@@ -123,33 +108,33 @@ func readTokenFromKeyring(keyringType, tokenName string) (*authtokens.AuthToken,
 
 	switch keyringType {
 	case NoneKeyring:
-		return nil, nil
+		memoryToken.Lock()
+		defer memoryToken.Unlock()
+		return memoryToken.token, nil
 
 	case WincredKeyring, KeychainKeyring:
 		token, err = zkeyring.Get(StoredTokenName, tokenName)
+		if errors.Is(err, zkeyring.ErrNotFound) {
+			// not logged in yet, like the other keyrings
+			return nil, nil
+		}
 		if err != nil {
-			if err == zkeyring.ErrNotFound {
-				return nil, errors.Join(fmt.Errorf("no saved credential found"), err)
-			} else {
-				return nil, errors.Join(fmt.Errorf("error reading auth token from keyring"), err)
-			}
+			return nil, errors.Join(fmt.Errorf("error reading auth token from keyring"), err)
 		}
 
 	default:
-		krConfig := nkeyring.Config{
-			LibSecretCollectionName: LoginCollection,
-			PassPrefix:              PassPrefix,
-			AllowedBackends:         []nkeyring.BackendType{nkeyring.BackendType(keyringType)},
-		}
-
 		var kr nkeyring.Keyring
-		kr, err = nkeyring.Open(krConfig)
+		kr, err = openKeyring(keyringType)
 		if err != nil {
 			return nil, errors.Join(fmt.Errorf("error opening keyring"), err)
 		}
 
 		var item nkeyring.Item
 		item, err = kr.Get(tokenName)
+		if errors.Is(err, nkeyring.ErrKeyNotFound) {
+			// not logged in yet
+			return nil, nil
+		}
 		if err != nil {
 			return nil, errors.Join(fmt.Errorf("error reading auth token from keyring"), err)
 		}
@@ -158,24 +143,40 @@ func readTokenFromKeyring(keyringType, tokenName string) (*authtokens.AuthToken,
 	}
 
 	if token != "" {
-		tokenBytes, err := base64.RawStdEncoding.DecodeString(token)
-		switch {
-		case err != nil:
-			return nil, errors.Join(fmt.Errorf("error base64-unmarshaling stored token from system credential store"), err)
-		case len(tokenBytes) == 0:
-			return nil, errors.New("zero length token after decoding stored token from system credential store")
-		default:
-			var authToken authtokens.AuthToken
-			if err := json.Unmarshal(tokenBytes, &authToken); err != nil {
-				return nil, errors.Join(fmt.Errorf("error unmarshaling stored token information after reading from system credential store"), err)
-			} else {
-				return &authToken, nil
-			}
-		}
+		return decodeToken(token)
 	}
 	return nil, err
 }
 
+// encodeToken encodes the token the same way the boundary CLI stores it in
+// the keyring, base64 without padding of its JSON
+func encodeToken(token *authtokens.AuthToken) (string, error) {
+	marshaled, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawStdEncoding.EncodeToString(marshaled), nil
+}
+
+// decodeToken decodes a token stored by encodeToken or by the boundary CLI
+func decodeToken(stored string) (*authtokens.AuthToken, error) {
+	tokenBytes, err := base64.RawStdEncoding.DecodeString(stored)
+	switch {
+	case err != nil:
+		return nil, errors.Join(fmt.Errorf("error base64-unmarshaling stored token from system credential store"), err)
+	case len(tokenBytes) == 0:
+		return nil, errors.New("zero length token after decoding stored token from system credential store")
+	}
+
+	var authToken authtokens.AuthToken
+	if err := json.Unmarshal(tokenBytes, &authToken); err != nil {
+		return nil, errors.Join(fmt.Errorf("error unmarshaling stored token information after reading from system credential store"), err)
+	}
+
+	return &authToken, nil
+}
+
 func TokenIdFromToken(token string) (string, error) {
 	split := strings.Split(token, "_")
 	if len(split) < 3 {
@@ -193,31 +194,33 @@ func SaveTokenToKeyring(result *authmethods.AuthenticateResult) error {
 	keyringType, tokenName, err := discoverKeyringTokenInfo()
 	if err != nil {
 		return err
-	} else if keyringType != "none" && tokenName != "none" && keyringType != "" && tokenName != "" {
-		marshaled, err := json.Marshal(token)
+	} else if keyringType == NoneKeyring {
+		memoryToken.Lock()
+		memoryToken.token = token
+		memoryToken.Unlock()
+
+		// the token is only printed when asked for, it would end up in the
+		// terminal scrollback and in logs
+		fmt.Printf("keyring type is %q, the token is only kept for this run, log in with --print-token to reuse it\n", NoneKeyring)
+	} else if tokenName != "none" && keyringType != "" && tokenName != "" {
+		encoded, err := encodeToken(token)
 		if err != nil {
 			return err
 		}
 		switch keyringType {
 		case "wincred", "keychain":
-			if err := zkeyring.Set(StoredTokenName, tokenName, base64.RawStdEncoding.EncodeToString(marshaled)); err != nil {
+			if err := zkeyring.Set(StoredTokenName, tokenName, encoded); err != nil {
 				return err
 			}
 		default:
-			krConfig := nkeyring.Config{
-				LibSecretCollectionName: LoginCollection,
-				PassPrefix:              PassPrefix,
-				AllowedBackends:         []nkeyring.BackendType{nkeyring.BackendType(keyringType)},
-			}
-
-			kr, err := nkeyring.Open(krConfig)
+			kr, err := openKeyring(keyringType)
 			if err != nil {
 				return err
 			}
 
 			if err := kr.Set(nkeyring.Item{
 				Key:  tokenName,
-				Data: []byte(base64.RawStdEncoding.EncodeToString(marshaled)),
+				Data: []byte(encoded),
 			}); err != nil {
 				return err
 			}
@@ -230,3 +233,56 @@ func SaveTokenToKeyring(result *authmethods.AuthenticateResult) error {
 	// fmt.Printf("Token: %s\n", token.Token)
 	return nil
 }
+
+// Backend returns where the token is read from, "env" when it is set in
+// BOUNDARY_TOKEN, "token-file" or "stdin" when it is read from
+// BOUNDARY_TOKEN_FILE or the keyring type otherwise
+func Backend() (string, error) {
+	if os.Getenv(EnvToken) != "" {
+		return BackendEnv, nil
+	}
+
+	switch os.Getenv(EnvTokenFile) {
+	case "":
+	case StdinTokenFile:
+		return BackendStdin, nil
+	default:
+		return BackendTokenFile, nil
+	}
+
+	keyringType, _, err := discoverKeyringTokenInfo()
+	return keyringType, err
+}
+
+// DeleteTokenFromKeyring removes the stored token, it is not an error if
+// there is no token stored
+func DeleteTokenFromKeyring() error {
+	keyringType, tokenName, err := discoverKeyringTokenInfo()
+	if err != nil {
+		return err
+	}
+
+	switch keyringType {
+	case NoneKeyring:
+		memoryToken.Lock()
+		memoryToken.token = nil
+		memoryToken.Unlock()
+
+	case WincredKeyring, KeychainKeyring:
+		if err := zkeyring.Delete(StoredTokenName, tokenName); err != nil && err != zkeyring.ErrNotFound {
+			return errors.Join(fmt.Errorf("error deleting auth token from keyring"), err)
+		}
+
+	default:
+		kr, err := openKeyring(keyringType)
+		if err != nil {
+			return errors.Join(fmt.Errorf("error opening keyring"), err)
+		}
+
+		if err := kr.Remove(tokenName); err != nil && !errors.Is(err, nkeyring.ErrKeyNotFound) {
+			return errors.Join(fmt.Errorf("error deleting auth token from keyring"), err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,231 @@
+package keyring
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/boundary/api/authtokens"
+)
+
+func TestTokenName(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		want string
+	}{
+		{name: "default", want: DefaultTokenName},
+		{name: "env", env: "work", want: "work"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvTokenName, tt.env)
+
+			if got := TokenName(); got != tt.want {
+				t.Errorf("TokenName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadTokenFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{name: "token only", path: write("plain", "at_1234567890_abc"), want: "at_1234567890_abc"},
+		{name: "trailing newline", path: write("newline", "at_1234567890_abc\n"), want: "at_1234567890_abc"},
+		{name: "surrounding whitespace", path: write("spaces", "  at_1234567890_abc \r\n\n"), want: "at_1234567890_abc"},
+		{name: "empty", path: write("empty", ""), wantErr: true},
+		{name: "only whitespace", path: write("blank", " \n"), wantErr: true},
+		{name: "missing", path: filepath.Join(dir, "missing"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvToken, "")
+			t.Setenv(EnvTokenFile, tt.path)
+
+			token, err := GetBoundaryToken()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got token %q", token.Token)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if token.Token != tt.want {
+				t.Errorf("token = %q, want %q", token.Token, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadTokenFromStdin(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "token", input: "at_1234567890_abc\n", want: "at_1234567890_abc"},
+		{name: "empty", input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, w, err := os.Pipe()
+			if err != nil {
+				t.Fatal(err)
+			}
+			w.WriteString(tt.input)
+			w.Close()
+
+			stdin := os.Stdin
+			os.Stdin = r
+			t.Cleanup(func() {
+				os.Stdin = stdin
+				r.Close()
+				stdinToken.read = false
+			})
+
+			t.Setenv(EnvToken, "")
+			t.Setenv(EnvTokenFile, StdinTokenFile)
+
+			// stdin is read once, the token is kept for the next reads
+			for range 2 {
+				token, err := GetBoundaryToken()
+				if tt.wantErr {
+					if err == nil {
+						t.Fatalf("expected an error, got token %q", token.Token)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if token.Token != tt.want {
+					t.Errorf("token = %q, want %q", token.Token, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestTokenEnvOverridesTokenFile(t *testing.T) {
+	t.Setenv(EnvToken, "at_env_token")
+	t.Setenv(EnvTokenFile, filepath.Join(t.TempDir(), "missing"))
+
+	token, err := GetBoundaryToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Token != "at_env_token" {
+		t.Errorf("token = %q, want the one from %s", token.Token, EnvToken)
+	}
+}
+
+// boundaryCLIToken is a token as stored by "boundary authenticate": the JSON
+// of the auth token encoded with base64 without padding
+const boundaryCLIToken = "eyJpZCI6ImF0XzEyMzQ1Njc4OTAiLCJzY29wZV9pZCI6Imdsb2JhbCIsInRva2VuIjoiYXRfMTIzNDU2Nzg5MF9zZWNyZXQiLCJ1c2VyX2lkIjoidV8xMjM0NTY3ODkwIiwiYXV0aF9tZXRob2RfaWQiOiJhbXB3XzEyMzQ1Njc4OTAiLCJleHBpcmF0aW9uX3RpbWUiOiIyMDMwLTAxLTAyVDAzOjA0OjA1WiJ9"
+
+func TestDecodeBoundaryCLIToken(t *testing.T) {
+	token, err := decodeToken(boundaryCLIToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := authtokens.AuthToken{
+		Id:             "at_1234567890",
+		ScopeId:        "global",
+		Token:          "at_1234567890_secret",
+		UserId:         "u_1234567890",
+		AuthMethodId:   "ampw_1234567890",
+		ExpirationTime: time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	if token.Id != want.Id || token.ScopeId != want.ScopeId || token.Token != want.Token ||
+		token.UserId != want.UserId || token.AuthMethodId != want.AuthMethodId || !token.ExpirationTime.Equal(want.ExpirationTime) {
+		t.Errorf("decoded %+v, want %+v", token, want)
+	}
+}
+
+func TestEncodeTokenRoundTrip(t *testing.T) {
+	token := &authtokens.AuthToken{
+		Id:             "at_1234567890",
+		ScopeId:        "global",
+		Token:          "at_1234567890_secret",
+		UserId:         "u_1234567890",
+		AuthMethodId:   "ampw_1234567890",
+		ExpirationTime: time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	encoded, err := encodeToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the boundary CLI decodes without padding and reads the API field names
+	raw, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatalf("encoded token is not unpadded base64: %v", err)
+	}
+	fields := map[string]any{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		"id":              token.Id,
+		"token":           token.Token,
+		"auth_method_id":  token.AuthMethodId,
+		"expiration_time": "2030-01-02T03:04:05Z",
+	} {
+		if fields[name] != want {
+			t.Errorf("field %s = %v, want %q", name, fields[name], want)
+		}
+	}
+
+	decoded, err := decodeToken(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Token != token.Token || decoded.Id != token.Id || !decoded.ExpirationTime.Equal(token.ExpirationTime) {
+		t.Errorf("round trip returned %+v, want %+v", decoded, token)
+	}
+}
+
+func TestDecodeTokenErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		stored string
+	}{
+		{name: "not base64", stored: "not base64!"},
+		{name: "padded base64", stored: base64.StdEncoding.EncodeToString([]byte(`{"token":"x"}`))},
+		{name: "not json", stored: base64.RawStdEncoding.EncodeToString([]byte("token"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeToken(tt.stored); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
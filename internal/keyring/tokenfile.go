@@ -0,0 +1,65 @@
+package keyring
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/boundary/api/authtokens"
+)
+
+const (
+	// StdinTokenFile reads the token from stdin when set as the token file
+	StdinTokenFile = "-"
+
+	BackendEnv       = "env"
+	BackendTokenFile = "token-file"
+	BackendStdin     = "stdin"
+)
+
+// stdinToken holds the token read from stdin, stdin can only be read once
+var stdinToken struct {
+	sync.Mutex
+	read  bool
+	token string
+	err   error
+}
+
+// readTokenFile reads a token from a file, or from stdin if the path is "-",
+// the file only has the token, e.g. the output of "boundary config get-token"
+func readTokenFile(path string) (*authtokens.AuthToken, error) {
+	var token string
+	var err error
+	if path == StdinTokenFile {
+		path = "stdin"
+		token, err = readStdinToken()
+	} else {
+		var data []byte
+		data, err = os.ReadFile(path)
+		token = strings.TrimSpace(string(data))
+	}
+
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("could not read token from %s: %w", path, err)
+	case token == "":
+		return nil, fmt.Errorf("no token in %s", path)
+	}
+
+	return &authtokens.AuthToken{Token: token}, nil
+}
+
+func readStdinToken() (string, error) {
+	stdinToken.Lock()
+	defer stdinToken.Unlock()
+
+	if !stdinToken.read {
+		data, err := io.ReadAll(os.Stdin)
+		stdinToken.token, stdinToken.err = strings.TrimSpace(string(data)), err
+		stdinToken.read = true
+	}
+
+	return stdinToken.token, stdinToken.err
+}
@@ -0,0 +1,60 @@
+package keyringcmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/AndreZiviani/boundary-fuzzy/internal/keyring"
+	"github.com/urfave/cli/v2"
+)
+
+func Command() *cli.Command {
+	command := cli.Command{
+		Name:  "keyring",
+		Usage: "Keyring Utilities",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "doctor",
+				Usage: "Show which keyring backends are available and why the others are not",
+				Description: "The backend is chosen with BOUNDARY_KEYRING_TYPE, \"auto\" picks the OS keyring\n" +
+					"on windows and macOS and the first available of pass, secret-service and\n" +
+					"file elsewhere. The file backend is encrypted with the passphrase in\n" +
+					keyring.EnvKeyringPassword + " or asked in the terminal, \"none\" keeps the\n" +
+					"token in memory or reads it from BOUNDARY_TOKEN.",
+				Action: Doctor,
+			},
+		},
+	}
+
+	return &command
+}
+
+func Doctor(c *cli.Context) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "BACKEND\tAVAILABLE\tDETAILS")
+	for _, backend := range keyring.Backends() {
+		available, details := "yes", backend.Detail
+		if backend.Err != nil {
+			available, details = "no", backend.Err.Error()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", backend.Name, available, details)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	configured := os.Getenv(keyring.EnvKeyringType)
+	if configured == "" {
+		configured = keyring.AutoKeyring
+	}
+
+	selected, err := keyring.Backend()
+	if err != nil {
+		fmt.Printf("\n%s=%s: %s\n", keyring.EnvKeyringType, configured, err)
+		return nil
+	}
+
+	fmt.Printf("\n%s=%s: using %s\n", keyring.EnvKeyringType, configured, selected)
+	return nil
+}
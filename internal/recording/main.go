@@ -0,0 +1,72 @@
+package recording
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+func Command() *cli.Command {
+	command := cli.Command{
+		Name:  "recording",
+		Usage: "Session Recording Utilities",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "list",
+				Usage:  "List recorded sessions",
+				Action: ListRecordings,
+			},
+			{
+				Name:      "replay",
+				Usage:     "Replay a recorded session",
+				ArgsUsage: "<recording>",
+				Flags: []cli.Flag{
+					&cli.Float64Flag{
+						Name:  "speed",
+						Usage: "playback speed multiplier",
+						Value: 1,
+					},
+					&cli.DurationFlag{
+						Name:  "idle-limit",
+						Usage: "maximum time to wait between events, 0 to disable",
+						Value: 2 * time.Second,
+					},
+				},
+				Action: ReplayRecording,
+			},
+		},
+	}
+
+	return &command
+}
+
+func ListRecordings(c *cli.Context) error {
+	recordings, err := List()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tMODIFIED\tSIZE")
+	for _, recording := range recordings {
+		fmt.Fprintf(w, "%s\t%s\t%d\n", recording.Name(), recording.ModTime().Format(time.RFC3339), recording.Size())
+	}
+
+	return w.Flush()
+}
+
+func ReplayRecording(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("expected exactly one recording to replay")
+	}
+
+	file, err := Resolve(c.Args().First())
+	if err != nil {
+		return err
+	}
+
+	return Replay(file, os.Stdout, c.Float64("speed"), c.Duration("idle-limit"))
+}
@@ -0,0 +1,40 @@
+package recording
+
+import (
+	"bytes"
+	"os"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPTY returns the master and slave sides of a new pseudo terminal
+func openPTY() (*os.File, *os.File, error) {
+	ptmx, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fd := uintptr(ptmx.Fd())
+	for _, request := range []uintptr{unix.TIOCPTYGRANT, unix.TIOCPTYUNLK} {
+		if _, _, errno := unix.Syscall(unix.SYS_IOCTL, fd, request, 0); errno != 0 {
+			ptmx.Close()
+			return nil, nil, errno
+		}
+	}
+
+	name := make([]byte, 128)
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, fd, unix.TIOCPTYGNAME, uintptr(unsafe.Pointer(&name[0]))); errno != 0 {
+		ptmx.Close()
+		return nil, nil, errno
+	}
+
+	tty, err := os.OpenFile(string(name[:bytes.IndexByte(name, 0)]), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		ptmx.Close()
+		return nil, nil, err
+	}
+
+	return ptmx, tty, nil
+}
@@ -0,0 +1,44 @@
+package recording
+
+import (
+	"io"
+	"os/exec"
+)
+
+// Recorder wraps an interactive command so its terminal output is recorded,
+// it implements tea.ExecCommand
+type Recorder struct {
+	cmd   *exec.Cmd
+	file  string
+	title string
+
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// NewRecorder returns a command that records cmd to file when run
+func NewRecorder(cmd *exec.Cmd, file, title string) *Recorder {
+	return &Recorder{
+		cmd:   cmd,
+		file:  file,
+		title: title,
+	}
+}
+
+func (c *Recorder) SetStdin(r io.Reader) {
+	c.stdin = r
+}
+
+func (c *Recorder) SetStdout(w io.Writer) {
+	c.stdout = w
+}
+
+func (c *Recorder) SetStderr(w io.Writer) {
+	c.stderr = w
+}
+
+// File returns the path of the recording
+func (c *Recorder) File() string {
+	return c.file
+}
@@ -0,0 +1,13 @@
+//go:build !linux && !darwin
+
+package recording
+
+// Run runs the command without recording it, pseudo terminals are not
+// supported on this platform
+func (c *Recorder) Run() error {
+	c.cmd.Stdin = c.stdin
+	c.cmd.Stdout = c.stdout
+	c.cmd.Stderr = c.stderr
+
+	return c.cmd.Run()
+}
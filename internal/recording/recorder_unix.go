@@ -0,0 +1,113 @@
+//go:build linux || darwin
+
+package recording
+
+import (
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/muesli/cancelreader"
+	"golang.org/x/sys/unix"
+	"golang.org/x/term"
+)
+
+// Run starts the command attached to a new pseudo terminal and copies its
+// input and output to the real terminal while recording the output
+func (c *Recorder) Run() error {
+	ptmx, tty, err := openPTY()
+	if err != nil {
+		return err
+	}
+	defer ptmx.Close()
+
+	c.cmd.Stdin = tty
+	c.cmd.Stdout = tty
+	c.cmd.Stderr = tty
+	c.cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true}
+
+	width, height := 80, 24
+	stdin, isFile := c.stdin.(*os.File)
+	isTerminal := isFile && term.IsTerminal(int(stdin.Fd()))
+
+	// resize copies the size of the real terminal to the pseudo terminal
+	resize := func() {
+		ws, err := unix.IoctlGetWinsize(int(stdin.Fd()), unix.TIOCGWINSZ)
+		if err != nil {
+			return
+		}
+		_ = unix.IoctlSetWinsize(int(ptmx.Fd()), unix.TIOCSWINSZ, ws)
+		width, height = int(ws.Col), int(ws.Row)
+	}
+
+	if isTerminal {
+		state, err := term.MakeRaw(int(stdin.Fd()))
+		if err != nil {
+			tty.Close()
+			return err
+		}
+		defer term.Restore(int(stdin.Fd()), state)
+
+		resize()
+	}
+
+	writer, err := newCastWriter(c.file, width, height, c.title)
+	if err != nil {
+		tty.Close()
+		return err
+	}
+	defer writer.Close()
+
+	if isTerminal {
+		winch := make(chan os.Signal, 1)
+		signal.Notify(winch, syscall.SIGWINCH)
+		defer func() {
+			signal.Stop(winch)
+			close(winch)
+		}()
+
+		go func() {
+			for range winch {
+				resize()
+				writer.Resize(width, height)
+			}
+		}()
+	}
+
+	if err := c.cmd.Start(); err != nil {
+		tty.Close()
+		return err
+	}
+	// the child has its own copy now
+	tty.Close()
+
+	// the input must be cancelable, otherwise we would steal the next key
+	// from the TUI after the command exits
+	input, err := cancelreader.NewReader(c.stdin)
+	if err != nil {
+		c.cmd.Process.Kill()
+		c.cmd.Wait()
+		return err
+	}
+	defer input.Close()
+	go io.Copy(ptmx, input)
+
+	outputDone := make(chan struct{})
+	go func() {
+		defer close(outputDone)
+		// reading fails with EIO once the child closes the terminal
+		io.Copy(io.MultiWriter(c.stdout, writer), ptmx)
+	}()
+
+	err = c.cmd.Wait()
+	select {
+	case <-outputDone:
+	case <-time.After(time.Second):
+		// something else is holding the terminal open, stop recording
+	}
+	input.Cancel()
+
+	return err
+}
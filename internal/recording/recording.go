@@ -0,0 +1,298 @@
+// package recording records interactive sessions in asciicast v2 format
+package recording
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/AndreZiviani/boundary-fuzzy/internal/config"
+)
+
+const (
+	FolderName = "recordings"
+	Extension  = ".cast"
+)
+
+// unsafeChars matches characters that should not be used in file names
+var unsafeChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+type header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Folder returns the folder where recordings are stored, creating it if needed
+func Folder() (string, error) {
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return "", err
+	}
+
+	configFolder, err := cfg.ConfigFolder()
+	if err != nil {
+		return "", err
+	}
+
+	folder := path.Join(configFolder, FolderName)
+	if err := os.MkdirAll(folder, 0700); err != nil {
+		return "", err
+	}
+
+	return folder, nil
+}
+
+// NewFileName returns the file name of a new recording
+func NewFileName(target, sessionId string) string {
+	name := fmt.Sprintf("%s_%s_%s", time.Now().Format("20060102T150405"), target, sessionId)
+	return unsafeChars.ReplaceAllString(name, "-") + Extension
+}
+
+// Prune removes recordings older than the retention, a zero retention keeps
+// every recording
+func Prune(retention time.Duration) error {
+	if retention <= 0 {
+		return nil
+	}
+
+	folder, err := Folder()
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != Extension {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		if time.Since(info.ModTime()) > retention {
+			if err := os.Remove(path.Join(folder, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// List returns the recordings stored in the recordings folder, newest first
+func List() ([]os.FileInfo, error) {
+	folder, err := Folder()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		return nil, err
+	}
+
+	recordings := []os.FileInfo{}
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != Extension {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		recordings = append(recordings, info)
+	}
+
+	sort.Slice(recordings, func(i, j int) bool {
+		return recordings[i].ModTime().After(recordings[j].ModTime())
+	})
+
+	return recordings, nil
+}
+
+// castWriter writes output events of an asciicast v2 file
+type castWriter struct {
+	mu      sync.Mutex
+	file    *os.File
+	start   time.Time
+	pending []byte
+}
+
+func newCastWriter(file string, width, height int, title string) (*castWriter, error) {
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &castWriter{file: f, start: time.Now()}
+
+	h := header{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: w.start.Unix(),
+		Title:     title,
+		Env: map[string]string{
+			"SHELL": os.Getenv("SHELL"),
+			"TERM":  os.Getenv("TERM"),
+		},
+	}
+	if err := json.NewEncoder(f).Encode(h); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (w *castWriter) event(kind, data string) error {
+	line, err := json.Marshal([]any{time.Since(w.start).Seconds(), kind, data})
+	if err != nil {
+		return err
+	}
+
+	_, err = w.file.Write(append(line, '\n'))
+	return err
+}
+
+// Write records terminal output, incomplete UTF-8 sequences are kept until
+// the next write so they are not mangled by the JSON encoder
+func (w *castWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	data := append(w.pending, p...)
+	w.pending = nil
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				w.pending = append([]byte{}, data[i:]...)
+				data = data[:i]
+			}
+			break
+		}
+	}
+
+	if len(data) == 0 {
+		return len(p), nil
+	}
+
+	return len(p), w.event("o", string(data))
+}
+
+// Resize records a terminal resize
+func (w *castWriter) Resize(width, height int) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.event("r", fmt.Sprintf("%dx%d", width, height))
+}
+
+func (w *castWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.pending) > 0 {
+		w.event("o", string(w.pending))
+	}
+
+	return w.file.Close()
+}
+
+// Resolve returns the path of a recording given its path or its name in the
+// recordings folder
+func Resolve(name string) (string, error) {
+	if _, err := os.Stat(name); err == nil {
+		return name, nil
+	}
+
+	folder, err := Folder()
+	if err != nil {
+		return "", err
+	}
+
+	if !strings.HasSuffix(name, Extension) {
+		name += Extension
+	}
+
+	file := path.Join(folder, path.Base(name))
+	if _, err := os.Stat(file); err != nil {
+		return "", fmt.Errorf("recording %q not found", name)
+	}
+
+	return file, nil
+}
+
+// Replay writes the output of a recording to w respecting its timing, idle
+// periods are capped to idleLimit
+func Replay(file string, w io.Writer, speed float64, idleLimit time.Duration) error {
+	if speed <= 0 {
+		return errors.New("speed must be greater than zero")
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	if !scanner.Scan() {
+		return fmt.Errorf("%s is empty", file)
+	}
+
+	var h header
+	if err := json.Unmarshal(scanner.Bytes(), &h); err != nil || h.Version != 2 {
+		return fmt.Errorf("%s is not an asciicast v2 file", file)
+	}
+
+	last := 0.0
+	for scanner.Scan() {
+		var event []any
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil || len(event) != 3 {
+			return fmt.Errorf("invalid event in %s: %s", file, scanner.Text())
+		}
+
+		at, _ := event[0].(float64)
+		kind, _ := event[1].(string)
+		data, _ := event[2].(string)
+
+		delay := time.Duration((at - last) / speed * float64(time.Second))
+		if idleLimit > 0 && delay > idleLimit {
+			delay = idleLimit
+		}
+		time.Sleep(delay)
+		last = at
+
+		if kind == "o" {
+			if _, err := io.WriteString(w, data); err != nil {
+				return err
+			}
+		}
+	}
+
+	return scanner.Err()
+}
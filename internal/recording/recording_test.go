@@ -0,0 +1,259 @@
+package recording
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readCast parses a recording into its header and events
+func readCast(t *testing.T, file string) (header, [][]any) {
+	t.Helper()
+
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		t.Fatalf("%s is empty", file)
+	}
+
+	var h header
+	if err := json.Unmarshal(scanner.Bytes(), &h); err != nil {
+		t.Fatalf("could not parse the header %q: %s", scanner.Text(), err)
+	}
+
+	events := [][]any{}
+	for scanner.Scan() {
+		var event []any
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("could not parse the event %q: %s", scanner.Text(), err)
+		}
+		events = append(events, event)
+	}
+
+	return h, events
+}
+
+// eventData returns the kind and data of the events, checking their format
+func eventData(t *testing.T, events [][]any) []string {
+	t.Helper()
+
+	last := 0.0
+	data := []string{}
+	for _, event := range events {
+		if len(event) != 3 {
+			t.Fatalf("event %v is not [time, kind, data]", event)
+		}
+		at, ok := event[0].(float64)
+		if !ok || at < last {
+			t.Fatalf("event %v has an invalid or decreasing time", event)
+		}
+		last = at
+
+		kind, ok1 := event[1].(string)
+		value, ok2 := event[2].(string)
+		if !ok1 || !ok2 {
+			t.Fatalf("event %v does not have a string kind and data", event)
+		}
+		data = append(data, kind+":"+value)
+	}
+
+	return data
+}
+
+func TestCastWriter(t *testing.T) {
+	file := path.Join(t.TempDir(), "session"+Extension)
+
+	start := time.Now()
+	w, err := newCastWriter(file, 120, 40, "prod/postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snowman := []byte("☃")
+	writes := [][]byte{
+		[]byte("$ psql\r\n"),
+		// a multi-byte character split between two reads of the terminal
+		append([]byte("a"), snowman[:1]...),
+		snowman[1:],
+	}
+	for _, data := range writes {
+		if n, err := w.Write(data); err != nil || n != len(data) {
+			t.Fatalf("Write() = %d, %v", n, err)
+		}
+	}
+	if err := w.Resize(100, 30); err != nil {
+		t.Fatal(err)
+	}
+	// left incomplete, it is written when the recording is closed
+	if _, err := w.Write(snowman[:2]); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	h, events := readCast(t, file)
+	if h.Version != 2 || h.Width != 120 || h.Height != 40 || h.Title != "prod/postgres" {
+		t.Fatalf("header is %+v", h)
+	}
+	if h.Timestamp < start.Unix() || h.Timestamp > time.Now().Unix() {
+		t.Fatalf("header timestamp %d is not the start of the recording", h.Timestamp)
+	}
+
+	// the JSON encoder replaces each byte of the incomplete character
+	want := []string{"o:$ psql\r\n", "o:a", "o:☃", "r:100x30", "o:\ufffd\ufffd"}
+	if got := eventData(t, events); !slices.Equal(got, want) {
+		t.Fatalf("events are %q, want %q", got, want)
+	}
+}
+
+func TestCastWriterDoesNotOverwrite(t *testing.T) {
+	file := path.Join(t.TempDir(), "session"+Extension)
+	if err := os.WriteFile(file, []byte("existing"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := newCastWriter(file, 80, 24, ""); err == nil {
+		t.Fatal("an existing recording was overwritten")
+	}
+}
+
+func TestReplay(t *testing.T) {
+	file := path.Join(t.TempDir(), "session"+Extension)
+	content := strings.Join([]string{
+		`{"version": 2, "width": 80, "height": 24, "timestamp": 1700000000}`,
+		`[0.1, "o", "hello "]`,
+		`[0.2, "r", "100x30"]`,
+		`[3600.2, "o", "world\r\n"]`,
+	}, "\n") + "\n"
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	start := time.Now()
+	// the hour between the events is capped by the idle limit
+	if err := Replay(file, &out, 10, 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("replay took %s, the idle limit was not applied", elapsed)
+	}
+	if out.String() != "hello world\r\n" {
+		t.Fatalf("replayed %q", out.String())
+	}
+}
+
+func TestReplayRecorded(t *testing.T) {
+	file := path.Join(t.TempDir(), "session"+Extension)
+	w, err := newCastWriter(file, 80, 24, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("first\r\n"))
+	w.Write([]byte("second\r\n"))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := Replay(file, &out, 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "first\r\nsecond\r\n" {
+		t.Fatalf("replayed %q", out.String())
+	}
+}
+
+func TestReplayErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		speed   float64
+	}{
+		{name: "zero speed", content: `{"version": 2}` + "\n", speed: 0},
+		{name: "empty", content: "", speed: 1},
+		{name: "asciicast v1", content: `{"version": 1}` + "\n", speed: 1},
+		{name: "not json", content: "hello\n", speed: 1},
+		{name: "invalid event", content: `{"version": 2}` + "\n" + `[0.1, "o"]` + "\n", speed: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := path.Join(t.TempDir(), "session"+Extension)
+			if err := os.WriteFile(file, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := Replay(file, &bytes.Buffer{}, tt.speed, 0); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestPrune(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	folder, err := Folder()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	files := map[string]time.Time{
+		"old" + Extension:    now.Add(-8 * 24 * time.Hour),
+		"recent" + Extension: now.Add(-6 * 24 * time.Hour),
+		"new" + Extension:    now,
+		// only recordings are removed
+		"old.txt": now.Add(-30 * 24 * time.Hour),
+	}
+	for name, modified := range files {
+		file := path.Join(folder, name)
+		if err := os.WriteFile(file, nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(file, modified, modified); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	remaining := func() []string {
+		entries, err := os.ReadDir(folder)
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := []string{}
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		return names
+	}
+
+	// a zero retention keeps every recording
+	if err := Prune(0); err != nil {
+		t.Fatal(err)
+	}
+	if names := remaining(); len(names) != len(files) {
+		t.Fatalf("Prune(0) removed files, %q are left", names)
+	}
+
+	if err := Prune(7 * 24 * time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"new" + Extension, "old.txt", "recent" + Extension}
+	if names := remaining(); !slices.Equal(names, want) {
+		t.Fatalf("%q are left, want %q", names, want)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !unix
+
+package run
+
+import (
+	"os/exec"
+)
+
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// terminate kills the process right away, SIGTERM is not supported on this platform
+func terminate(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
+
+func kill(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
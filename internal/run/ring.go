@@ -0,0 +1,84 @@
+package run
+
+import (
+	"bytes"
+	"sync"
+)
+
+// RingBuffer is an io.Writer that keeps the last lines written to it,
+// incomplete lines are kept until their newline arrives or Flush is called
+type RingBuffer struct {
+	mu      sync.Mutex
+	lines   []string
+	next    int
+	full    bool
+	partial []byte
+}
+
+// NewRingBuffer returns a buffer holding at most size lines
+func NewRingBuffer(size int) *RingBuffer {
+	if size < 1 {
+		size = 1
+	}
+
+	return &RingBuffer{lines: make([]string, size)}
+}
+
+func (r *RingBuffer) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data := p
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			r.partial = append(r.partial, data...)
+			break
+		}
+
+		r.partial = append(r.partial, data[:i]...)
+		r.push(string(bytes.TrimSuffix(r.partial, []byte("\r"))))
+		r.partial = r.partial[:0]
+		data = data[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// Flush stores the pending incomplete line, if any
+func (r *RingBuffer) Flush() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.partial) > 0 {
+		r.push(string(r.partial))
+		r.partial = r.partial[:0]
+	}
+}
+
+func (r *RingBuffer) push(line string) {
+	r.lines[r.next] = line
+	r.next = (r.next + 1) % len(r.lines)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Lines returns the buffered lines from the oldest to the newest, including
+// the pending incomplete line
+func (r *RingBuffer) Lines() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lines := make([]string, 0, len(r.lines)+1)
+	if r.full {
+		lines = append(lines, r.lines[r.next:]...)
+	}
+	lines = append(lines, r.lines[:r.next]...)
+
+	if len(r.partial) > 0 {
+		lines = append(lines, string(r.partial))
+	}
+
+	return lines
+}
@@ -0,0 +1,111 @@
+package run
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRingBufferLines(t *testing.T) {
+	tests := []struct {
+		name   string
+		size   int
+		writes []string
+		flush  bool
+		want   []string
+	}{
+		{
+			name:   "complete lines",
+			size:   3,
+			writes: []string{"a\nb\n"},
+			want:   []string{"a", "b"},
+		},
+		{
+			name:   "lines split across writes",
+			size:   3,
+			writes: []string{"fi", "rst\nsec", "ond\n"},
+			want:   []string{"first", "second"},
+		},
+		{
+			name:   "carriage returns are removed",
+			size:   3,
+			writes: []string{"a\r\nb\r\n"},
+			want:   []string{"a", "b"},
+		},
+		{
+			name:   "trailing partial line",
+			size:   3,
+			writes: []string{"a\nno newline"},
+			want:   []string{"a", "no newline"},
+		},
+		{
+			name:   "trailing partial line flushed",
+			size:   3,
+			writes: []string{"a\nno newline"},
+			flush:  true,
+			want:   []string{"a", "no newline"},
+		},
+		{
+			name:   "wraparound keeps the newest lines",
+			size:   3,
+			writes: []string{"1\n2\n3\n4\n5\n"},
+			want:   []string{"3", "4", "5"},
+		},
+		{
+			name:   "wraparound with a partial line",
+			size:   2,
+			writes: []string{"1\n2\n3\n4"},
+			want:   []string{"2", "3", "4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRingBuffer(tt.size)
+			for _, w := range tt.writes {
+				if n, err := r.Write([]byte(w)); err != nil || n != len(w) {
+					t.Fatalf("Write(%q) = %d, %v", w, n, err)
+				}
+			}
+			if tt.flush {
+				r.Flush()
+			}
+
+			if got := r.Lines(); !slices.Equal(got, tt.want) {
+				t.Errorf("Lines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRingBufferFlushTwice(t *testing.T) {
+	r := NewRingBuffer(3)
+	r.Write([]byte("partial"))
+	r.Flush()
+	r.Flush()
+	r.Write([]byte("next\n"))
+
+	want := []string{"partial", "next"}
+	if got := r.Lines(); !slices.Equal(got, want) {
+		t.Errorf("Lines() = %q, want %q", got, want)
+	}
+}
+
+func TestRingBufferDefaultOutputLines(t *testing.T) {
+	r := NewRingBuffer(DefaultOutputLines)
+
+	var output strings.Builder
+	for i := range DefaultOutputLines + 10 {
+		fmt.Fprintf(&output, "line %d\n", i)
+	}
+	r.Write([]byte(output.String()))
+
+	lines := r.Lines()
+	if len(lines) != DefaultOutputLines {
+		t.Fatalf("got %d lines, want %d", len(lines), DefaultOutputLines)
+	}
+	if lines[0] != "line 10" || lines[len(lines)-1] != fmt.Sprintf("line %d", DefaultOutputLines+9) {
+		t.Errorf("got lines %q to %q", lines[0], lines[len(lines)-1])
+	}
+}
@@ -0,0 +1,198 @@
+package run
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultOutputLines is the number of output lines kept by a task
+	DefaultOutputLines = 1000
+
+	// DefaultGracePeriod is how long Stop waits after SIGTERM before killing the process
+	DefaultGracePeriod = 5 * time.Second
+)
+
+type State int
+
+const (
+	Running State = iota
+	Exited
+	Failed
+	Stopped
+)
+
+func (s State) String() string {
+	switch s {
+	case Running:
+		return "running"
+	case Exited:
+		return "exited"
+	case Failed:
+		return "failed"
+	case Stopped:
+		return "stopped"
+	}
+
+	return "unknown"
+}
+
+// Task supervises a non interactive process, its stdout and stderr are kept
+// in a ring buffer
+type Task struct {
+	Command string
+	Args    []string
+	Cmd     *exec.Cmd
+	Output  *RingBuffer
+
+	mu       sync.Mutex
+	state    State
+	exitCode int
+	err      error
+	stopping bool
+	doneCh   chan struct{}
+}
+
+// Start starts cmd and supervises it until it exits, keeping the last lines
+// of its output. The command stdout and stderr must not be set
+func Start(cmd *exec.Cmd, lines int) (*Task, error) {
+	if cmd.Stdout != nil || cmd.Stderr != nil {
+		return nil, errors.New("stdout and stderr must not be set")
+	}
+
+	t := &Task{
+		Command:  cmd.Path,
+		Args:     cmd.Args,
+		Cmd:      cmd,
+		Output:   NewRingBuffer(lines),
+		state:    Running,
+		exitCode: -1,
+		doneCh:   make(chan struct{}),
+	}
+
+	// exec copies the output from a pipe, WaitDelay makes sure Wait returns
+	// even if a child process inherited the pipe and kept it open
+	cmd.Stdout = t.Output
+	cmd.Stderr = t.Output
+	if cmd.WaitDelay == 0 {
+		cmd.WaitDelay = DefaultGracePeriod
+	}
+	setProcessGroup(cmd)
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("could not start %s: %w", cmd.Path, err)
+	}
+
+	go t.wait()
+
+	return t, nil
+}
+
+// wait is the only place where the process is waited on
+func (t *Task) wait() {
+	err := t.Cmd.Wait()
+	t.Output.Flush()
+
+	// the process exited but a child kept the output open, that is not a failure
+	if errors.Is(err, exec.ErrWaitDelay) {
+		err = nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.Cmd.ProcessState != nil {
+		t.exitCode = t.Cmd.ProcessState.ExitCode()
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case t.stopping:
+		t.state = Stopped
+	case err != nil && !errors.As(err, &exitErr):
+		t.state = Failed
+		t.err = err
+	case t.exitCode == 0:
+		t.state = Exited
+	default:
+		t.state = Failed
+	}
+
+	close(t.doneCh)
+}
+
+// Stop asks the process and its children to terminate with SIGTERM and kills
+// them if they are still running after the grace period, it returns once the
+// process exited
+func (t *Task) Stop(grace time.Duration) {
+	t.mu.Lock()
+	if t.state != Running {
+		t.mu.Unlock()
+		return
+	}
+	t.stopping = true
+	t.mu.Unlock()
+
+	if err := terminate(t.Cmd); err != nil {
+		_ = kill(t.Cmd)
+	}
+
+	select {
+	case <-t.doneCh:
+		return
+	case <-time.After(grace):
+	}
+
+	_ = kill(t.Cmd)
+	<-t.doneCh
+}
+
+// Done is closed when the process exits
+func (t *Task) Done() <-chan struct{} {
+	return t.doneCh
+}
+
+func (t *Task) State() State {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.state
+}
+
+// ExitCode returns the exit code of the process, or -1 if it is still
+// running or was terminated by a signal
+func (t *Task) ExitCode() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.exitCode
+}
+
+// Err returns the error that prevented the process from being waited on, a
+// non zero exit code is not an error
+func (t *Task) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.err
+}
+
+// Status returns a short human readable description of the process state
+func (t *Task) Status() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch {
+	case t.state == Running:
+		return fmt.Sprintf("%s (pid %d)", t.state, t.Cmd.Process.Pid)
+	case t.err != nil:
+		return fmt.Sprintf("%s: %s", t.state, t.err)
+	case t.exitCode >= 0:
+		return fmt.Sprintf("%s (exit status %d)", t.state, t.exitCode)
+	}
+
+	return t.state.String()
+}
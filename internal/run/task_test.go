@@ -0,0 +1,165 @@
+//go:build unix
+
+package run
+
+import (
+	"os/exec"
+	"slices"
+	"testing"
+	"time"
+)
+
+func startShell(t *testing.T, script string) *Task {
+	t.Helper()
+
+	task, err := Start(exec.Command("sh", "-c", script), DefaultOutputLines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { task.Stop(0) })
+
+	return task
+}
+
+func waitDone(t *testing.T, task *Task) {
+	t.Helper()
+
+	select {
+	case <-task.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("the process did not exit")
+	}
+}
+
+// waitOutput waits until the process printed line, e.g. once a trap is set
+func waitOutput(t *testing.T, task *Task, line string) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !slices.Contains(task.Output.Lines(), line) {
+		if time.Now().After(deadline) {
+			t.Fatalf("%q not in the output %q", line, task.Output.Lines())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestTaskExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		state    State
+		exitCode int
+	}{
+		{name: "success", script: "exit 0", state: Exited, exitCode: 0},
+		{name: "failure", script: "exit 3", state: Failed, exitCode: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := startShell(t, tt.script)
+			waitDone(t, task)
+
+			if task.State() != tt.state {
+				t.Errorf("State() = %s, want %s", task.State(), tt.state)
+			}
+			if task.ExitCode() != tt.exitCode {
+				t.Errorf("ExitCode() = %d, want %d", task.ExitCode(), tt.exitCode)
+			}
+			// a non zero exit code is not an error
+			if task.Err() != nil {
+				t.Errorf("Err() = %v, want nil", task.Err())
+			}
+		})
+	}
+}
+
+func TestTaskOutput(t *testing.T) {
+	task := startShell(t, "echo out; echo err >&2; printf partial")
+	waitDone(t, task)
+
+	want := []string{"out", "err", "partial"}
+	if got := task.Output.Lines(); !slices.Equal(got, want) {
+		t.Errorf("Lines() = %q, want %q", got, want)
+	}
+}
+
+func TestTaskStartError(t *testing.T) {
+	if _, err := Start(exec.Command("/nonexistent/command"), DefaultOutputLines); err == nil {
+		t.Fatal("expected an error starting a missing command")
+	}
+
+	cmd := exec.Command("true")
+	cmd.Stdout = &RingBuffer{}
+	if _, err := Start(cmd, DefaultOutputLines); err == nil {
+		t.Fatal("expected an error when stdout is set")
+	}
+}
+
+func TestTaskRunning(t *testing.T) {
+	task := startShell(t, "sleep 10")
+
+	if task.State() != Running {
+		t.Errorf("State() = %s, want %s", task.State(), Running)
+	}
+	if task.ExitCode() != -1 {
+		t.Errorf("ExitCode() = %d, want -1", task.ExitCode())
+	}
+
+	select {
+	case <-task.Done():
+		t.Fatal("Done() closed while the process is running")
+	default:
+	}
+}
+
+func TestTaskStopTerminates(t *testing.T) {
+	task := startShell(t, "echo ready; sleep 10")
+	waitOutput(t, task, "ready")
+
+	start := time.Now()
+	task.Stop(5 * time.Second)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Stop took %s, the process should exit on SIGTERM", elapsed)
+	}
+
+	waitDone(t, task)
+	if task.State() != Stopped {
+		t.Errorf("State() = %s, want %s", task.State(), Stopped)
+	}
+}
+
+func TestTaskStopKillsAfterGracePeriod(t *testing.T) {
+	// the shell ignores SIGTERM, reports it and keeps running
+	task := startShell(t, `trap 'echo term' TERM; echo ready; while :; do sleep 0.05; done`)
+	waitOutput(t, task, "ready")
+
+	grace := 300 * time.Millisecond
+	start := time.Now()
+	task.Stop(grace)
+	elapsed := time.Since(start)
+
+	waitDone(t, task)
+	if elapsed < grace {
+		t.Errorf("Stop returned after %s, before the grace period", elapsed)
+	}
+	if !slices.Contains(task.Output.Lines(), "term") {
+		t.Errorf("the process did not get SIGTERM, output %q", task.Output.Lines())
+	}
+	if task.State() != Stopped {
+		t.Errorf("State() = %s, want %s", task.State(), Stopped)
+	}
+	if task.ExitCode() != -1 {
+		t.Errorf("ExitCode() = %d, want -1 after SIGKILL", task.ExitCode())
+	}
+}
+
+func TestTaskStopAfterExit(t *testing.T) {
+	task := startShell(t, "exit 2")
+	waitDone(t, task)
+
+	task.Stop(time.Second)
+	if task.State() != Failed {
+		t.Errorf("State() = %s, want %s", task.State(), Failed)
+	}
+}
@@ -1,9 +1,11 @@
 package target
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/AndreZiviani/boundary-fuzzy/internal/client"
 	"github.com/AndreZiviani/boundary-fuzzy/internal/target/tui"
-	"github.com/hashicorp/boundary/api/targets"
 	"github.com/urfave/cli/v2"
 )
 
@@ -13,11 +15,26 @@ func Command() *cli.Command {
 		Usage: "Target Utilities",
 		Subcommands: []*cli.Command{
 			{
-				Name:   "connect",
-				Usage:  "Connect to a target",
-				Flags:  []cli.Flag{},
+				Name:  "connect",
+				Usage: "Connect to a target",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{Name: "no-cache", Usage: "Wait for the target list instead of showing the cached one"},
+				},
 				Action: TargetTui,
 			},
+			{
+				Name:      "exec",
+				Usage:     "Run a command through a target session",
+				ArgsUsage: "<target> -- <command> [args...]",
+				Description: "The target can be its id, its name or \"scope/name\". The placeholders\n" +
+					"{{host}}, {{port}}, {{username}} and {{password}} are replaced in the\n" +
+					"command arguments and environment, the session is canceled when the\n" +
+					"command exits and its exit code is returned.",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{Name: "yes", Aliases: []string{"y"}, Usage: "Skip the confirmation required by the target policy"},
+				},
+				Action: TargetExec,
+			},
 		},
 	}
 
@@ -26,17 +43,45 @@ func Command() *cli.Command {
 
 func TargetTui(c *cli.Context) error {
 	boundaryClient, token, err := client.NewBoundaryClient(c.Context)
-	if err != nil {
+	// the cached targets can be shown while the controller is unreachable
+	if err != nil && !errors.Is(err, client.ErrUnreachable) {
 		return err
 	}
 
-	targetClient := targets.NewClient(boundaryClient)
+	tui.Tui(c.Context, boundaryClient, token, !c.Bool("no-cache"))
+	return nil
+}
+
+func TargetExec(c *cli.Context) error {
+	// flag parsing stops at the target so the "--" separator is kept
+	command := c.Args().Tail()
+	if len(command) > 0 && command[0] == "--" {
+		command = command[1:]
+	}
 
-	targets, err := targetClient.List(c.Context, "global", targets.WithRecursive(true))
+	if c.Args().First() == "" || len(command) == 0 {
+		return fmt.Errorf("usage: %s %s", c.Command.HelpName, c.Command.ArgsUsage)
+	}
+
+	boundaryClient, token, err := client.NewBoundaryClient(c.Context)
+	if err != nil {
+		return err
+	}
+
+	exitCode, err := tui.Exec(c.Context, tui.ExecInput{
+		BoundaryClient: boundaryClient,
+		BoundaryToken:  token,
+		Target:         c.Args().First(),
+		Command:        command,
+		Yes:            c.Bool("yes"),
+	})
 	if err != nil {
 		return err
 	}
 
-	tui.Tui(c.Context, targets, boundaryClient, token)
+	if exitCode != 0 {
+		return cli.Exit("", exitCode)
+	}
+
 	return nil
 }
@@ -1,8 +1,53 @@
 package tui
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/AndreZiviani/boundary-fuzzy/internal/config"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+)
+
+const (
+	globalBindings    = "global"
+	targetsBindings   = "targets"
+	connectedBindings = "connected"
+	favoriteBindings  = "favorites"
+
+	defaultKeyPreset = "default"
+)
 
 var (
+	bindingQuit = binding{
+		name: "quit",
+		binding: key.NewBinding(
+			key.WithKeys("q", "ctrl+c"),
+			key.WithHelp("q", "quit"),
+		),
+	}
+	bindingNextTab = binding{
+		name: "next_tab",
+		binding: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "go to next tab"),
+		),
+	}
+	bindingHelp = binding{
+		name: "help",
+		binding: key.NewBinding(
+			key.WithKeys("?"),
+			key.WithHelp("?", "show key bindings"),
+		),
+	}
+	bindingNotifications = binding{
+		name: "notifications",
+		binding: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "show notification history"),
+		),
+	}
 	bindingShell = binding{
 		name: "shell",
 		binding: key.NewBinding(
@@ -10,6 +55,13 @@ var (
 			key.WithHelp("s", "open a shell"),
 		),
 	}
+	bindingSubShell = binding{
+		name: "subshell",
+		binding: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "open a sub-shell with the connection environment"),
+		),
+	}
 	bindingConnect = binding{
 		name: "connect",
 		binding: key.NewBinding(
@@ -17,6 +69,13 @@ var (
 			key.WithHelp("c", "connect to target"),
 		),
 	}
+	bindingCancel = binding{
+		name: "cancel",
+		binding: key.NewBinding(
+			key.WithKeys("ctrl+x"),
+			key.WithHelp("ctrl+x", "cancel connecting to target"),
+		),
+	}
 	bindingFavorite = binding{
 		name: "favorite",
 		binding: key.NewBinding(
@@ -42,7 +101,7 @@ var (
 		name: "disconnect",
 		binding: key.NewBinding(
 			key.WithKeys("d"),
-			key.WithHelp("d", "disconnect from target"),
+			key.WithHelp("d", "disconnect from target or cancel connecting"),
 		),
 	}
 	bindingReconnect = binding{
@@ -52,6 +111,13 @@ var (
 			key.WithHelp("r", "reconnect to target"),
 		),
 	}
+	bindingLogs = binding{
+		name: "logs",
+		binding: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "show background process output"),
+		),
+	}
 	bindingDelete = binding{
 		name: "delete",
 		binding: key.NewBinding(
@@ -75,7 +141,204 @@ var (
 	}
 )
 
+// bindingTabs is the order in which the bindings of each tab are shown in the help view
+var bindingTabs = []string{globalBindings, targetsBindings, connectedBindings, favoriteBindings}
+
+var defaultKeyBindings = map[string][]binding{
+	globalBindings:    {bindingQuit, bindingNextTab, bindingHelp, bindingNotifications},
+	targetsBindings:   {bindingShell, bindingSubShell, bindingConnect, bindingCancel, bindingFavorite, bindingInfo, bindingRefresh},
+	connectedBindings: {bindingDisconnect, bindingReconnect, bindingInfo, bindingLogs, bindingFavorite},
+	favoriteBindings:  {bindingShell, bindingSubShell, bindingDelete, bindingConnect, bindingCancel, bindingFavoriteUp, bindingFavoriteDown, bindingInfo},
+}
+
+// keyPresets override the default keys per tab, user bindings are applied on top of them
+var keyPresets = map[string]map[string]map[string][]string{
+	defaultKeyPreset: {},
+	"k9s": {
+		targetsBindings: {
+			"shell":    {"s"},
+			"connect":  {"F"},
+			"favorite": {"ctrl+b"},
+			"info":     {"enter", "y"},
+			"refresh":  {"ctrl+r"},
+		},
+		connectedBindings: {
+			"disconnect": {"ctrl+d"},
+			"reconnect":  {"ctrl+k"},
+			"info":       {"enter", "y"},
+			"logs":       {"L"},
+			"favorite":   {"ctrl+b"},
+		},
+		favoriteBindings: {
+			"shell":   {"s"},
+			"delete":  {"ctrl+d"},
+			"connect": {"F"},
+			"up":      {"K"},
+			"down":    {"J"},
+			"info":    {"enter", "y"},
+		},
+	},
+	"lazygit": {
+		globalBindings: {
+			"next_tab": {"tab", "]"},
+		},
+		targetsBindings: {
+			"shell":    {"o"},
+			"subshell": {"!"},
+			"connect":  {" "},
+			"favorite": {"f"},
+			"info":     {"enter"},
+			"refresh":  {"R"},
+		},
+		connectedBindings: {
+			"disconnect": {"d"},
+			"reconnect":  {"r"},
+			"info":       {"enter"},
+			"favorite":   {"f"},
+		},
+		favoriteBindings: {
+			"shell":    {"o"},
+			"subshell": {"!"},
+			"delete":   {"d"},
+			"connect":  {" "},
+			"up":       {"ctrl+k"},
+			"down":     {"ctrl+j"},
+			"info":     {"enter"},
+		},
+	},
+}
+
 type binding struct {
-	name        string
-	binding 	 key.Binding
-}
\ No newline at end of file
+	name    string
+	binding key.Binding
+}
+
+// keyBindings holds the effective bindings of each tab
+type keyBindings map[string][]binding
+
+// newKeyBindings builds the bindings from the defaults, the configured preset
+// and the user overrides, returning an error if any key is bound twice
+func newKeyBindings(settings config.Settings) (keyBindings, error) {
+	preset, ok := keyPresets[settings.KeyPreset]
+	if !ok {
+		return nil, fmt.Errorf("unknown key preset %q", settings.KeyPreset)
+	}
+
+	bindings := keyBindings{}
+	for tab, defaults := range defaultKeyBindings {
+		bindings[tab] = slices.Clone(defaults)
+	}
+
+	for _, overrides := range []map[string]map[string][]string{preset, settings.KeyBindings} {
+		if err := bindings.apply(overrides); err != nil {
+			return nil, err
+		}
+	}
+
+	return bindings, bindings.validate()
+}
+
+func (k keyBindings) apply(overrides map[string]map[string][]string) error {
+	for tab, tabOverrides := range overrides {
+		tabBindings, ok := k[tab]
+		if !ok {
+			return fmt.Errorf("unknown key binding tab %q", tab)
+		}
+
+		for name, keys := range tabOverrides {
+			i := slices.IndexFunc(tabBindings, func(b binding) bool { return b.name == name })
+			if i < 0 {
+				return fmt.Errorf("unknown key binding %q on the %s tab", name, tab)
+			}
+
+			if len(keys) == 0 {
+				return fmt.Errorf("no keys set for binding %q on the %s tab", name, tab)
+			}
+
+			tabBindings[i].binding = key.NewBinding(
+				key.WithKeys(keys...),
+				key.WithHelp(keysHelp(keys), tabBindings[i].binding.Help().Desc),
+			)
+		}
+	}
+
+	return nil
+}
+
+// validate checks that no key is bound to more than one action on the same
+// tab, taking the global and list navigation bindings into account
+func (k keyBindings) validate() error {
+	for _, tab := range bindingTabs[1:] {
+		used := map[string]string{}
+		for _, b := range slices.Concat(listBindings(), k[globalBindings], k[tab]) {
+			for _, keyName := range b.binding.Keys() {
+				if other, ok := used[keyName]; ok {
+					return fmt.Errorf("key %q is bound to both %q and %q on the %s tab", keyName, other, b.name, tab)
+				}
+				used[keyName] = b.name
+			}
+		}
+	}
+
+	return nil
+}
+
+// keysHelp returns a printable representation of a list of keys
+func keysHelp(keys []string) string {
+	printable := make([]string, len(keys))
+	for i, k := range keys {
+		if k == " " {
+			k = "space"
+		}
+		printable[i] = k
+	}
+	return strings.Join(printable, "/")
+}
+
+func (k keyBindings) get(tab, name string) key.Binding {
+	for _, b := range k[tab] {
+		if b.name == name {
+			return b.binding
+		}
+	}
+
+	return key.NewBinding(key.WithDisabled())
+}
+
+// listKeyMap returns the navigation bindings used by every list
+func listKeyMap() list.KeyMap {
+	keyMap := list.DefaultKeyMap()
+
+	// remove some keys from the default keymap
+	keyMap.PrevPage = key.NewBinding(
+		key.WithKeys("left", "h", "pgup"),
+		key.WithHelp("←/h/pgup", "prev page"),
+	)
+	keyMap.NextPage = key.NewBinding(
+		key.WithKeys("right", "l", "pgdown"),
+		key.WithHelp("→/l/pgdn", "next page"),
+	)
+
+	// help and quit are handled globally
+	keyMap.ShowFullHelp.SetEnabled(false)
+	keyMap.CloseFullHelp.SetEnabled(false)
+	keyMap.Quit.SetEnabled(false)
+	keyMap.ForceQuit.SetEnabled(false)
+
+	return keyMap
+}
+
+func listBindings() []binding {
+	keyMap := listKeyMap()
+
+	return []binding{
+		{name: "cursor_up", binding: keyMap.CursorUp},
+		{name: "cursor_down", binding: keyMap.CursorDown},
+		{name: "prev_page", binding: keyMap.PrevPage},
+		{name: "next_page", binding: keyMap.NextPage},
+		{name: "go_to_start", binding: keyMap.GoToStart},
+		{name: "go_to_end", binding: keyMap.GoToEnd},
+		{name: "filter", binding: keyMap.Filter},
+		{name: "clear_filter", binding: keyMap.ClearFilter},
+	}
+}
@@ -1,36 +1,178 @@
 package tui
 
 import (
+	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/AndreZiviani/boundary-fuzzy/internal/client"
+	"github.com/AndreZiviani/boundary-fuzzy/internal/config"
 	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/boundary/api"
+	"github.com/hashicorp/boundary/api/authtokens"
 	"github.com/hashicorp/boundary/api/sessions"
 	"github.com/hashicorp/boundary/api/targets"
 )
 
-func (t *tui) refreshTargets() error {
-	targetsResult, err := t.targetsClient.List(t.ctx, "global", targets.WithRecursive(true))
+// msgTargets carries the result of listing the targets, the client and token
+// are set when they had to be renewed, cachedAt is set when the targets were
+// read from the cache
+type msgTargets struct {
+	result         *targets.TargetListResult
+	boundaryClient *api.Client
+	boundaryToken  *authtokens.AuthToken
+	cachedAt       time.Time
+	err            error
+}
+
+func fetchTargets(ctx context.Context, targetsClient *targets.Client) msgTargets {
+	targetsResult, err := targetsClient.List(ctx, "global", targets.WithRecursive(true))
+	if err == nil {
+		return msgTargets{result: targetsResult}
+	}
+
+	// our token is probably invalid, we should refresh it
+	boundaryClient, token, err := client.NewBoundaryClient(ctx)
 	if err != nil {
-		// our token is probably invalid, we should refresh it
-		boundaryClient, token, err := client.NewBoundaryClient(t.ctx)
-		if err != nil || token == nil {
-			return err
+		return msgTargets{err: err}
+	}
+	if token == nil {
+		return msgTargets{err: errUnauthenticated}
+	}
+
+	targetsResult, err = targets.NewClient(boundaryClient).List(ctx, "global", targets.WithRecursive(true))
+	return msgTargets{result: targetsResult, boundaryClient: boundaryClient, boundaryToken: token, err: err}
+}
+
+// cacheTargets saves the listed targets for the next start
+func cacheTargets(cfg config.Config, addr string, msg msgTargets) {
+	if msg.err == nil {
+		client.CacheTargets(cfg, addr, msg.result.Items)
+	}
+}
+
+// refreshTargetsCmd lists the targets without blocking the UI
+func (t *tui) refreshTargetsCmd() tea.Cmd {
+	t.refreshing = true
+
+	ctx, targetsClient := t.ctx, t.targetsClient
+	cfg, addr := t.config, t.boundaryClient.Addr()
+	return tea.Batch(
+		t.startSpinner(),
+		func() tea.Msg {
+			msg := fetchTargets(ctx, targetsClient)
+			cacheTargets(cfg, addr, msg)
+			return msg
+		},
+	)
+}
+
+func (t *tui) refreshTargets() error {
+	msg := fetchTargets(t.ctx, t.targetsClient)
+	cacheTargets(t.config, t.boundaryClient.Addr(), msg)
+	return t.setTargets(msg)
+}
+
+// loadCachedTargets shows the targets from the last run, they are marked as
+// stale until they are refreshed
+func (t *tui) loadCachedTargets() bool {
+	cache, ok := client.CachedTargets(t.config, t.boundaryClient.Addr())
+	if !ok {
+		return false
+	}
+
+	err := t.setTargets(msgTargets{
+		result:   &targets.TargetListResult{Items: cache.Targets},
+		cachedAt: cache.SavedAt,
+	})
+	return err == nil
+}
+
+// maxTargetChanges limits the names listed when the targets change
+const maxTargetChanges = 5
+
+// targetChanges describes the targets added and removed by a refresh
+func targetChanges(before, after []list.Item) string {
+	if len(before) == 0 {
+		return ""
+	}
+
+	names := func(items []list.Item) map[string]string {
+		names := make(map[string]string, len(items))
+		for _, item := range items {
+			if target, ok := item.(*Target); ok {
+				names[target.target.Id] = target.target.Name
+			}
 		}
+		return names
+	}
+	beforeNames, afterNames := names(before), names(after)
 
-		t.boundaryToken = token
-		t.boundaryClient = boundaryClient
-		t.sessionsClient = sessions.NewClient(boundaryClient)
-		t.targetsClient = targets.NewClient(boundaryClient)
+	diff := func(from, to map[string]string) []string {
+		missing := []string{}
+		for id, name := range from {
+			if _, ok := to[id]; !ok {
+				missing = append(missing, name)
+			}
+		}
+		slices.Sort(missing)
+		return missing
+	}
 
-		targetsResult, err = t.targetsClient.List(t.ctx, "global", targets.WithRecursive(true))
-		if err != nil {
-			return err
+	changes := []string{}
+	for _, change := range []struct {
+		verb  string
+		names []string
+	}{
+		{"added", diff(afterNames, beforeNames)},
+		{"removed", diff(beforeNames, afterNames)},
+	} {
+		if len(change.names) == 0 {
+			continue
+		}
+		if len(change.names) > maxTargetChanges {
+			change.names = append(change.names[:maxTargetChanges], "...")
 		}
+		changes = append(changes, fmt.Sprintf("%s %s", change.verb, strings.Join(change.names, ", ")))
+	}
+
+	if len(changes) == 0 {
+		return ""
+	}
+
+	return ", " + strings.Join(changes, "; ")
+}
+
+// setClient replaces the client used for new targets
+func (t *tui) setClient(boundaryClient *api.Client, boundaryToken *authtokens.AuthToken) {
+	t.boundaryToken = boundaryToken
+	t.boundaryClient = boundaryClient
+	t.sessionsClient = sessions.NewClient(boundaryClient)
+	t.targetsClient = targets.NewClient(boundaryClient)
+}
+
+func (t *tui) setTargets(msg msgTargets) error {
+	t.refreshing = false
+
+	if msg.boundaryClient != nil {
+		t.setClient(msg.boundaryClient, msg.boundaryToken)
+	}
+
+	if msg.err != nil {
+		return msg.err
+	}
+	t.cachedAt = msg.cachedAt
+
+	userId := ""
+	if t.boundaryToken != nil {
+		userId = t.boundaryToken.UserId
 	}
 
-	tuiTargets := make([]list.Item, 0, len(targetsResult.Items))
-	for _, target := range targetsResult.Items {
+	tuiTargets := make([]list.Item, 0, len(msg.result.Items))
+	for _, target := range msg.result.Items {
 		tuiTargets = append(
 			tuiTargets,
 			&Target{
@@ -39,6 +181,11 @@ func (t *tui) refreshTargets() error {
 				target:         target,
 				sessionsClient: t.sessionsClient,
 				targetClient:   t.targetsClient,
+				sessions:       t.sessions,
+				settings:       t.config.Settings,
+				policy:         t.config.Settings.PolicyFor(target.Scope.Name, target.Name),
+				userId:         userId,
+				progress:       &progress{},
 			})
 	}
 
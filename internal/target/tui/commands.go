@@ -6,14 +6,15 @@ import (
 	"os/exec"
 	"strconv"
 
+	"github.com/AndreZiviani/boundary-fuzzy/internal/config"
 	"github.com/hashicorp/boundary/api/targets"
 )
 
-func NewPSQLCommand(host string, port int, credentials []*targets.SessionCredential) *exec.Cmd {
+func NewPSQLCommand(host string, port int, credentials []*targets.SessionCredential, settings config.Settings, readOnly bool) *exec.Cmd {
 	args := []string{
 		"-h", host,
 		"-p", strconv.Itoa(port),
-		"-d", "postgres",
+		"-d", settings.PostgresDatabase,
 	}
 
 	if len(credentials) > 0 {
@@ -27,10 +28,14 @@ func NewPSQLCommand(host string, port int, credentials []*targets.SessionCredent
 		cmd.Env = append(cmd.Env, fmt.Sprintf("PGPASSWORD=%s", credentials[0].Secret.Decoded["password"]))
 	}
 
+	if readOnly {
+		cmd.Env = append(cmd.Env, "PGOPTIONS=-c default_transaction_read_only=on")
+	}
+
 	return cmd
 }
 
-func NewMySQLCommand(host string, port int, credentials []*targets.SessionCredential) *exec.Cmd {
+func NewMySQLCommand(host string, port int, credentials []*targets.SessionCredential, settings config.Settings, readOnly bool) *exec.Cmd {
 	args := []string{
 		"-A",
 		"-h", host,
@@ -40,7 +45,10 @@ func NewMySQLCommand(host string, port int, credentials []*targets.SessionCreden
 	if len(credentials) > 0 {
 		args = append(args, "-u", credentials[0].Secret.Decoded["username"].(string))
 	}
-	args = append(args, "information_schema")
+	if readOnly {
+		args = append(args, "--safe-updates", "--init-command=SET SESSION TRANSACTION READ ONLY")
+	}
+	args = append(args, settings.MySQLDatabase)
 
 	cmd := exec.Command("mysql", args...)
 	cmd.Env = os.Environ()
@@ -52,9 +60,10 @@ func NewMySQLCommand(host string, port int, credentials []*targets.SessionCreden
 	return cmd
 }
 
-func NewRedisCommand(host string, port int, credentials []*targets.SessionCredential) *exec.Cmd {
+func NewRedisCommand(host string, port int, credentials []*targets.SessionCredential, settings config.Settings, readOnly bool) *exec.Cmd {
 	cmd := exec.Command(
 		"redis-cli",
+		"-h", host,
 		"-p", strconv.Itoa(port),
 	)
 	cmd.Env = os.Environ()
@@ -62,14 +71,21 @@ func NewRedisCommand(host string, port int, credentials []*targets.SessionCreden
 	return cmd
 }
 
-func NewClickHouseCommand(host string, port int, credentials []*targets.SessionCredential) *exec.Cmd {
+func NewClickHouseCommand(host string, port int, credentials []*targets.SessionCredential, settings config.Settings, readOnly bool) *exec.Cmd {
 	args := []string{
 		"--secure",
-		"--accept-invalid-certificate",
 		"--host", host,
 		"--port", strconv.Itoa(port),
 	}
 
+	if settings.ClickHouseAcceptInvalidCertificate {
+		args = append(args, "--accept-invalid-certificate")
+	}
+
+	if readOnly {
+		args = append(args, "--readonly=1")
+	}
+
 	if len(credentials) > 0 {
 		args = append(args, "--user", credentials[0].Secret.Decoded["username"].(string))
 	}
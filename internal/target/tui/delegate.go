@@ -3,7 +3,6 @@ package tui
 import (
 	"fmt"
 	"io"
-	"maps"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -13,6 +12,14 @@ import (
 	"github.com/charmbracelet/x/ansi"
 )
 
+// listItem is implemented by the targets and the sessions listed in the tabs
+type listItem interface {
+	list.Item
+	Title(tab sessionState) (string, string)
+	Description(tab sessionState) (string, string)
+	IsConnected() bool
+}
+
 type delegateUpdateFunc = func(t targetDelegate, msg tea.Msg, m *list.Model) tea.Cmd
 type delegateRenderFunc = func(t targetDelegate, w io.Writer, m list.Model, index int, item list.Item)
 
@@ -32,23 +39,25 @@ func (c DelegateKeyMap) FullHelp() [][]key.Binding {
 	}
 }
 
-func NewDelegate(keyMap map[string]key.Binding, tab sessionState, updater delegateUpdateFunc, renderer delegateRenderFunc) (*targetDelegate, *DelegateKeyMap) {
-	keys := make([]string, 0, len(keyMap))
-	values := make([]key.Binding, 0, len(keyMap))
-	for k, v := range keyMap {
-		keys = append(keys, k)
-		values = append(values, v)
+func NewDelegate(bindings []binding, tab sessionState, updater delegateUpdateFunc, renderer delegateRenderFunc) (*targetDelegate, *DelegateKeyMap) {
+	keyMap := make(map[string]key.Binding, len(bindings))
+	keys := make([]string, 0, len(bindings))
+	values := make([]key.Binding, 0, len(bindings))
+	for _, b := range bindings {
+		keyMap[b.name] = b.binding
+		keys = append(keys, b.name)
+		values = append(values, b.binding)
 	}
 
 	km := &DelegateKeyMap{
-		binding: maps.Clone(keyMap),
+		binding: keyMap,
 		keys:    keys,
 		values:  values,
 	}
 
 	d := &targetDelegate{
 		ShowDescription: true,
-		Styles:          list.NewDefaultItemStyles(),
+		Styles:          itemStyles,
 		ShortHelpFunc:   func() []key.Binding { return values },
 		FullHelpFunc:    func() [][]key.Binding { return [][]key.Binding{values} },
 		height:          2,
@@ -105,7 +114,7 @@ func (td targetDelegate) Render(w io.Writer, m list.Model, index int, item list.
 		s = &td.Styles
 	)
 
-	target, ok := item.(*Target)
+	target, ok := item.(listItem)
 	if !ok {
 		return
 	}
@@ -122,14 +131,14 @@ func (td targetDelegate) Render(w io.Writer, m list.Model, index int, item list.
 	textwidth := m.Width() - s.NormalTitle.GetPaddingLeft() - s.NormalTitle.GetPaddingRight()
 
 	if rtitle != "" {
-		titleWidth := textwidth - len(rtitle)
-		if len(title) > titleWidth {
+		titleWidth := textwidth - ansi.StringWidth(rtitle)
+		if ansi.StringWidth(title) > titleWidth {
 			// Truncate title and add ellipsis
 			titleWidth = titleWidth - len(ellipsis)
 		}
 
 		tmpTitle := ansi.Truncate(title, titleWidth, ellipsis)
-		padding := textwidth - len(tmpTitle) - len(rtitle)
+		padding := max(0, textwidth-ansi.StringWidth(tmpTitle)-ansi.StringWidth(rtitle))
 		title = fmt.Sprintf("%s%s%s", tmpTitle, strings.Repeat(" ", padding), rtitle)
 	} else {
 		title = ansi.Truncate(title, textwidth, ellipsis)
@@ -138,10 +147,10 @@ func (td targetDelegate) Render(w io.Writer, m list.Model, index int, item list.
 	if td.ShowDescription {
 		descWidth := textwidth
 		if rdesc != "" {
-			descWidth = descWidth - len(rdesc)
+			descWidth = descWidth - ansi.StringWidth(rdesc)
 		}
 
-		if len(desc) > descWidth {
+		if ansi.StringWidth(desc) > descWidth {
 			// Truncate description and add ellipsis
 			descWidth = descWidth - len(ellipsis)
 		}
@@ -156,7 +165,7 @@ func (td targetDelegate) Render(w io.Writer, m list.Model, index int, item list.
 		nlines := len(lines)
 		lastLine := lines[nlines-1]
 
-		padding := textwidth - len(lastLine) - len(rdesc)
+		padding := max(0, textwidth-ansi.StringWidth(lastLine)-ansi.StringWidth(rdesc))
 		lines[nlines-1] = fmt.Sprintf("%s%s%s", lastLine, strings.Repeat(" ", padding), rdesc)
 		desc = strings.Join(lines, "\n")
 	}
@@ -183,7 +192,7 @@ func renderCustomItem(m list.Model, index int, item list.Item, title, desc strin
 		active     = true
 	)
 
-	if target, ok := item.(*Target); ok {
+	if target, ok := item.(listItem); ok {
 		active = target.IsConnected()
 	}
 
@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+)
+
+// env returns environment variables describing the session, using the
+// names understood by the most common clients
+func (s *SessionInfo) env() []string {
+	t := s.target
+	host := s.Address
+	port := strconv.Itoa(s.Port)
+
+	env := []string{
+		"BOUNDARY_PROXY_ADDR=" + net.JoinHostPort(host, port),
+		"BOUNDARY_PROXY_HOST=" + host,
+		"BOUNDARY_PROXY_PORT=" + port,
+		"BOUNDARY_TARGET_ID=" + t.target.Id,
+		"BOUNDARY_TARGET_NAME=" + t.target.Name,
+		"BOUNDARY_SESSION_ID=" + s.SessionId,
+		"PGHOST=" + host,
+		"PGPORT=" + port,
+		"MYSQL_HOST=" + host,
+		"MYSQL_TCP_PORT=" + port,
+		"REDISCLI_HOST=" + host,
+		"REDISCLI_PORT=" + port,
+	}
+
+	if len(s.Credentials) > 0 {
+		decoded := s.Credentials[0].Secret.Decoded
+		if username, ok := decoded["username"].(string); ok {
+			env = append(env,
+				"BOUNDARY_USERNAME="+username,
+				"PGUSER="+username,
+				"MYSQL_USER="+username,
+			)
+		}
+		if password, ok := decoded["password"].(string); ok {
+			env = append(env,
+				"BOUNDARY_PASSWORD="+password,
+				"PGPASSWORD="+password,
+				"MYSQL_PWD="+password,
+				"REDISCLI_AUTH="+password,
+			)
+		}
+	}
+
+	// most shells read the prompt from their rc files, BOUNDARY_FUZZY_TARGET
+	// can be used there to build a custom prompt
+	prefix := fmt.Sprintf("(%s) ", t.target.Name)
+	prompt := os.Getenv("PS1")
+	if prompt == "" {
+		prompt = `\w \$ `
+	}
+	env = append(env,
+		"BOUNDARY_FUZZY_TARGET="+t.target.Name,
+		"PS1="+prefix+prompt,
+		"PROMPT="+prefix+"%~ %# ",
+	)
+
+	return env
+}
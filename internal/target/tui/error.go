@@ -3,23 +3,55 @@ package tui
 import (
 	"fmt"
 
-	"github.com/charmbracelet/lipgloss"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/boundary/api"
 )
 
-func (t tui) HandleErrorView() string {
-	text := alertViewStyle.Render(
-		lipgloss.JoinHorizontal(
-			lipgloss.Left,
-			fmt.Sprintf("Failed to open shell: \n%s\n\nPress any key to return", errorStyle(t.message)),
-		),
-	)
-
-	paddingHeight := (t.height - lipgloss.Height(text)) / 2
-	paddingWidth := (t.width - lipgloss.Width(text)) / 2
-
-	return lipgloss.NewStyle().Padding(
-		paddingHeight-1,
-		paddingWidth,
-		0,
-	).Render(text)
+// opError is an error annotated with the operation that failed
+type opError struct {
+	op  string
+	err error
+}
+
+func (e *opError) Error() string {
+	return fmt.Sprintf("%s: %s", e.op, describeError(e.err))
+}
+
+func (e *opError) Unwrap() error {
+	return e.err
+}
+
+// newOpError annotates err with the operation that failed, nil errors stay nil
+func newOpError(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &opError{op: op, err: err}
+}
+
+// errorCmd reports a failed operation in the notification area
+func errorCmd(op string, err error) tea.Cmd {
+	return func() tea.Msg { return msgError{err: newOpError(op, err)} }
+}
+
+// describeError returns a readable message, Boundary API errors are reduced
+// to their status code, kind and message instead of the raw response body
+func describeError(err error) string {
+	apiErr := api.AsServerError(err)
+	if apiErr == nil {
+		return err.Error()
+	}
+
+	status := 0
+	if apiErr.Response() != nil {
+		status = apiErr.Response().StatusCode()
+	}
+
+	message := apiErr.Message
+	if message == "" {
+		message = "no details"
+	}
+
+	return fmt.Sprintf("%d %s: %s", status, apiErr.Kind, message)
 }
@@ -0,0 +1,256 @@
+package tui
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/AndreZiviani/boundary-fuzzy/internal/client"
+	"github.com/AndreZiviani/boundary-fuzzy/internal/config"
+	"github.com/hashicorp/boundary/api"
+	"github.com/hashicorp/boundary/api/authtokens"
+	"github.com/hashicorp/boundary/api/sessions"
+	"github.com/hashicorp/boundary/api/targets"
+	"golang.org/x/term"
+)
+
+type ExecInput struct {
+	BoundaryClient *api.Client
+	BoundaryToken  *authtokens.AuthToken
+
+	// Target is the target id, its name or its scope and name ("scope/name"),
+	// the scope can be the scope name or its full path
+	Target  string
+	Command []string
+
+	// Yes skips the confirmation required by the target policy
+	Yes bool
+}
+
+// Exec connects to a target and runs a command with the session details
+// substituted in its arguments and environment. The session is canceled when
+// the command exits and its exit code is returned
+func Exec(ctx context.Context, input ExecInput) (int, error) {
+	if len(input.Command) == 0 {
+		return 0, fmt.Errorf("missing command")
+	}
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return 0, err
+	}
+
+	targetsClient := targets.NewClient(input.BoundaryClient)
+	target, err := findTarget(ctx, input.BoundaryClient, targetsClient, input.Target)
+	if err != nil {
+		return 0, err
+	}
+
+	userId := ""
+	if input.BoundaryToken != nil {
+		userId = input.BoundaryToken.UserId
+	}
+
+	t := &Target{
+		title:          fmt.Sprintf("%s (%s)", target.Name, target.Scope.Name),
+		target:         target,
+		sessionsClient: sessions.NewClient(input.BoundaryClient),
+		targetClient:   targetsClient,
+		settings:       cfg.Settings,
+		policy:         cfg.Settings.PolicyFor(target.Scope.Name, target.Name),
+		userId:         userId,
+	}
+
+	if err := t.confirmExec(input.Yes); err != nil {
+		return 0, err
+	}
+
+	session, err := t.newSessionProxy(ctx, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := session.Terminate(); err != nil {
+			fmt.Fprintf(os.Stderr, "could not cancel session %s: %s\n", session.SessionId, err)
+		}
+	}()
+
+	replacer := session.templateReplacer()
+	args := make([]string, len(input.Command))
+	for i, arg := range input.Command {
+		args[i] = replacer.Replace(arg)
+	}
+
+	env := os.Environ()
+	for i, value := range env {
+		env[i] = replacer.Replace(value)
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Env = append(env, session.env()...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	start := time.Now()
+	err = runForwardingSignals(cmd)
+	session.logCommand(cmd, start, "", err)
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitCode(exitErr), nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return 0, nil
+}
+
+// exitCode returns the exit code of the command, 128 plus the signal number
+// if it was killed by a signal like shells do
+func exitCode(exitErr *exec.ExitError) int {
+	if ws, ok := exitErr.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		return 128 + int(ws.Signal())
+	}
+
+	return exitErr.ExitCode()
+}
+
+// runForwardingSignals runs cmd passing interrupts on to it, so the session is
+// only canceled after the command had a chance to exit cleanly
+func runForwardingSignals(cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	for {
+		select {
+		case sig := <-signals:
+			_ = cmd.Process.Signal(sig)
+		case err := <-done:
+			return err
+		}
+	}
+}
+
+// templateReplacer replaces the {{host}}, {{port}}, {{username}} and
+// {{password}} placeholders with the session details
+func (s *SessionInfo) templateReplacer() *strings.Replacer {
+	username, password := "", ""
+	if len(s.Credentials) > 0 {
+		decoded := s.Credentials[0].Secret.Decoded
+		username, _ = decoded["username"].(string)
+		password, _ = decoded["password"].(string)
+	}
+
+	return strings.NewReplacer(
+		"{{host}}", s.Address,
+		"{{port}}", strconv.Itoa(s.Port),
+		"{{username}}", username,
+		"{{password}}", password,
+	)
+}
+
+// confirmExec applies the target policy outside the TUI, asking on the
+// terminal when possible and requiring yes otherwise
+func (t *Target) confirmExec(yes bool) error {
+	if t.policy.Banner != "" {
+		fmt.Fprintln(os.Stderr, t.policy.Banner)
+	}
+
+	if !t.policy.Guarded() || yes {
+		return nil
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return fmt.Errorf("target %q requires confirmation, use --yes to run non-interactively", t.target.Name)
+	}
+
+	expected := "y"
+	if t.policy.Confirm {
+		expected = t.target.Name
+		fmt.Fprintf(os.Stderr, "Type the target name %q to continue: ", t.target.Name)
+	} else {
+		fmt.Fprintf(os.Stderr, "Continue connecting to %q? [y/N] ", t.target.Name)
+	}
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(answer) != expected {
+		return fmt.Errorf("aborted")
+	}
+
+	return nil
+}
+
+// findTarget resolves a target by id, name or scope and name, returning an
+// error if more than one target matches
+func findTarget(ctx context.Context, boundaryClient *api.Client, targetsClient *targets.Client, name string) (*targets.Target, error) {
+	targetsResult, err := targetsClient.List(ctx, "global", targets.WithRecursive(true))
+	if err != nil {
+		return nil, err
+	}
+
+	paths := map[string]string{}
+	if strings.Contains(name, "/") {
+		paths, err = client.ScopePaths(ctx, boundaryClient)
+		if err != nil {
+			// we may not be allowed to list scopes, fallback to the scope name
+			paths = map[string]string{}
+		}
+	}
+
+	matches := []*targets.Target{}
+	for _, target := range targetsResult.Items {
+		if target.Id == name {
+			return target, nil
+		}
+
+		candidates := []string{target.Name}
+		if target.Scope != nil {
+			candidates = append(candidates, target.Scope.Name+"/"+target.Name)
+		}
+		if path, ok := paths[target.ScopeId]; ok {
+			candidates = append(candidates, path+"/"+target.Name)
+		}
+
+		for _, candidate := range candidates {
+			if candidate == name {
+				matches = append(matches, target)
+				break
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("target %q not found", name)
+	case 1:
+		return matches[0], nil
+	}
+
+	found := make([]string, len(matches))
+	for i, target := range matches {
+		found[i] = client.TargetScopePath(target, paths) + "/" + target.Name
+	}
+	return nil, fmt.Errorf("target %q is ambiguous, use the scope to select one of: %s", name, strings.Join(found, ", "))
+}
@@ -0,0 +1,35 @@
+//go:build unix
+
+package tui
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   int
+	}{
+		{name: "exit", script: "exit 3", want: 3},
+		{name: "terminated", script: "kill -TERM $$", want: 143},
+		{name: "killed", script: "kill -KILL $$", want: 137},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := exec.Command("sh", "-c", tt.script).Run()
+
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) {
+				t.Fatalf("Run() = %v, want an exit error", err)
+			}
+			if got := exitCode(exitErr); got != tt.want {
+				t.Fatalf("exitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
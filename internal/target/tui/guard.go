@@ -0,0 +1,131 @@
+package tui
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// guard runs the action right away unless the target policy requires the user
+// to confirm it first
+func guard(target *Target, action func() tea.Cmd) tea.Cmd {
+	if !target.policy.Guarded() {
+		return action()
+	}
+
+	return func() tea.Msg { return msgConfirm{target: target, action: action} }
+}
+
+func shellAction(target *Target) func() tea.Cmd {
+	return target.Shell
+}
+
+func subShellAction(target *Target) func() tea.Cmd {
+	return target.SubShell
+}
+
+func connectAction(target *Target) func() tea.Cmd {
+	return func() tea.Cmd {
+		if target.progress.Busy() {
+			return notifyCmd(levelWarning, "already connecting to %s", target.target.Name)
+		}
+
+		return tea.Sequence(
+			// show the target in the connected tab while it connects
+			func() tea.Msg { return msgConnect{target: target} },
+			target.connectAsync(fmt.Sprintf("connect to %s", target.target.Name), 0, func(session *SessionInfo, _ *exec.Cmd) (tea.Cmd, error) {
+				return tea.Sequence(
+					func() tea.Msg { return msgConnect{target: target, session: session} },
+					notifyCmd(levelInfo, "connected to %s on port %d", target.target.Name, session.Port),
+				), nil
+			}),
+		)
+	}
+}
+
+func (t tui) startConfirm(msg msgConfirm) (tea.Model, tea.Cmd) {
+	t.pending = &msg
+	t.confirmInput = textinput.New()
+	t.confirmInput.Placeholder = msg.target.target.Name
+	t.SetState(confirmView)
+
+	return t, t.confirmInput.Focus()
+}
+
+func (t tui) confirmUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		return t.resize(size)
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return t, nil
+	}
+
+	confirmed := false
+	switch {
+	case keyMsg.Type == tea.KeyEsc || keyMsg.Type == tea.KeyCtrlC:
+		// cancelled
+
+	case t.pending.target.policy.Confirm:
+		if keyMsg.Type != tea.KeyEnter {
+			var cmd tea.Cmd
+			t.confirmInput, cmd = t.confirmInput.Update(msg)
+			return t, cmd
+		}
+
+		if t.confirmInput.Value() != t.pending.target.target.Name {
+			t.confirmInput.Reset()
+			return t, nil
+		}
+		confirmed = true
+
+	default:
+		// For simplicity's sake, we'll treat any key besides "y" as "no"
+		confirmed = keyMsg.String() == "y"
+	}
+
+	action := t.pending.action
+	t.pending = nil
+	t.state = t.previousState
+
+	if !confirmed {
+		return t, nil
+	}
+
+	return t, action()
+}
+
+func (t tui) HandleConfirmView() string {
+	target := t.pending.target
+
+	lines := []string{}
+	if target.policy.Banner != "" {
+		lines = append(lines, bannerStyle(target.policy.Banner), "")
+	}
+
+	if target.policy.Confirm {
+		lines = append(lines,
+			fmt.Sprintf("Type the target name %q to continue, esc to cancel", target.target.Name),
+			t.confirmInput.View(),
+		)
+	} else {
+		lines = append(lines, fmt.Sprintf("Continue connecting to %q? %s", target.target.Name, choiceStyle.Render("[y/N]")))
+	}
+
+	text := alertViewStyle.BorderForeground(environmentColor(target)).Render(
+		lipgloss.JoinVertical(lipgloss.Left, lines...),
+	)
+
+	paddingHeight := (t.height - lipgloss.Height(text)) / 2
+	paddingWidth := (t.width - lipgloss.Width(text)) / 2
+
+	return lipgloss.NewStyle().Padding(
+		max(0, paddingHeight-1),
+		max(0, paddingWidth),
+		0,
+	).Render(text)
+}
@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var helpTabNames = map[string]string{
+	globalBindings:    "Global",
+	targetsBindings:   targetsTabName,
+	connectedBindings: connectedTabName,
+	favoriteBindings:  favoritesTabName,
+}
+
+func (t tui) HandleHelpView() string {
+	sections := make([]string, 0, len(bindingTabs)+1)
+	for _, tab := range bindingTabs {
+		sections = append(sections, renderHelpSection(helpTabNames[tab], t.keyBindings[tab]))
+	}
+	sections = append(sections, renderHelpSection("Navigation", listBindings()))
+
+	// wrap sections into as many rows as needed to fit the window
+	rows := []string{}
+	row := []string{}
+	for _, section := range sections {
+		if len(row) > 0 && lipgloss.Width(lipgloss.JoinHorizontal(lipgloss.Top, append(row, section)...)) > t.width-4 {
+			rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+			row = []string{}
+		}
+		row = append(row, section)
+	}
+	rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...), choiceStyle.Render("Press any key to return"))
+
+	text := alertViewStyle.Padding(0, 1).Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+
+	paddingHeight := (t.height - lipgloss.Height(text)) / 2
+	paddingWidth := (t.width - lipgloss.Width(text)) / 2
+
+	return lipgloss.NewStyle().Padding(
+		max(0, paddingHeight-1),
+		max(0, paddingWidth),
+		0,
+	).Render(text)
+}
+
+func renderHelpSection(title string, bindings []binding) string {
+	keyWidth := 0
+	for _, b := range bindings {
+		keyWidth = max(keyWidth, lipgloss.Width(b.binding.Help().Key))
+	}
+
+	lines := []string{helpTitleStyle.Render(title)}
+	for _, b := range bindings {
+		help := b.binding.Help()
+		lines = append(lines, fmt.Sprintf("%s %s", helpKeyStyle.Width(keyWidth).Render(help.Key), help.Desc))
+	}
+
+	return lipgloss.NewStyle().PaddingRight(4).PaddingBottom(1).Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,172 @@
+package tui
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hashicorp/boundary/api/targets"
+)
+
+var (
+	httpPorts = map[int]string{
+		80:   "http",
+		8080: "http",
+		443:  "https",
+		8443: "https",
+	}
+
+	// httpNameHint matches target names that look like web endpoints
+	httpNameHint = regexp.MustCompile(`(?i)\bhttps?\b`)
+)
+
+// httpScheme returns the scheme of targets that should be opened in the
+// browser, using the "scheme" attribute, the default port or the target name
+func httpScheme(target *targets.Target) (string, bool) {
+	if scheme, ok := target.Attributes["scheme"].(string); ok && (scheme == "http" || scheme == "https") {
+		return scheme, true
+	}
+
+	if port, ok := target.Attributes["default_port"].(float64); ok {
+		if scheme, ok := httpPorts[int(port)]; ok {
+			return scheme, true
+		}
+	}
+
+	if match := httpNameHint.FindString(target.Name); match != "" {
+		return strings.ToLower(match), true
+	}
+
+	return "", false
+}
+
+// URL returns the address of the local proxy as an URL
+func (s *SessionInfo) URL(scheme string) string {
+	return fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(s.Address, strconv.Itoa(s.Port)))
+}
+
+// rewriteHost starts a plain HTTP proxy in front of the session proxy that
+// sets the Host header, and the TLS server name, to host. It is stopped when
+// the session ends and the URL to reach it is returned
+func (s *SessionInfo) rewriteHost(scheme, host string) (string, error) {
+	upstream, err := url.Parse(s.URL(scheme))
+	if err != nil {
+		return "", err
+	}
+
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(upstream)
+			r.Out.Host = host
+		},
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{ServerName: host},
+		},
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(s.Address, "0"))
+	if err != nil {
+		return "", err
+	}
+
+	server := &http.Server{Handler: proxy}
+	go server.Serve(listener)
+	go func() {
+		<-s.ctx.Done()
+		server.Close()
+	}()
+
+	return "http://" + listener.Addr().String(), nil
+}
+
+// msgConnected is sent when a target without a client was connected, url is
+// set for HTTP targets
+type msgConnected struct {
+	session *SessionInfo
+	url     string
+	err     error
+}
+
+func (t tui) connectedUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		return t.resize(size)
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return t, nil
+	}
+
+	session := t.connected.session
+	value := ""
+	switch keyMsg.String() {
+	case "a":
+		value = net.JoinHostPort(session.Address, strconv.Itoa(session.Port))
+	case "p":
+		value = strconv.Itoa(session.Port)
+	case "u":
+		value = t.connected.url
+	}
+
+	if value != "" {
+		if err := clipboard.WriteAll(value); err != nil {
+			t.message = fmt.Sprintf("could not copy to clipboard: %s", err)
+		} else {
+			t.message = fmt.Sprintf("copied %q to clipboard", value)
+		}
+		return t, nil
+	}
+
+	t.message = ""
+	t.connected = nil
+	t.state = t.previousState
+	return t, nil
+}
+
+func (t tui) HandleConnectedView() string {
+	session := t.connected.session
+	target := session.target
+
+	lines := []string{}
+	switch {
+	case t.connected.url != "" && t.connected.err == nil:
+		lines = append(lines, fmt.Sprintf("Opened %s in your browser", t.connected.url))
+	case t.connected.url != "":
+		lines = append(lines,
+			fmt.Sprintf("Failed to open your browser: %s", errorStyle(t.connected.err.Error())),
+			fmt.Sprintf("Please open %s", t.connected.url),
+		)
+	default:
+		lines = append(lines, fmt.Sprintf("There is no client for %q, it is available on %s", target.target.Name, net.JoinHostPort(session.Address, strconv.Itoa(session.Port))))
+	}
+
+	options := "[a] copy address  [p] copy port"
+	if t.connected.url != "" {
+		options += "  [u] copy url"
+	}
+	lines = append(lines, "", choiceStyle.Render(options+"  any other key to return"))
+
+	if t.message != "" {
+		lines = append(lines, messageStyle(t.message))
+	}
+
+	text := alertViewStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+
+	paddingHeight := (t.height - lipgloss.Height(text)) / 2
+	paddingWidth := (t.width - lipgloss.Width(text)) / 2
+
+	return lipgloss.NewStyle().Padding(
+		max(0, paddingHeight-1),
+		max(0, paddingWidth),
+		0,
+	).Render(text)
+}
@@ -0,0 +1,174 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const kubernetesPort = 6443
+
+// unsafeNameChars matches characters that should not be used in kubeconfig names
+var unsafeNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+type kubeconfig struct {
+	ApiVersion     string              `json:"apiVersion"`
+	Kind           string              `json:"kind"`
+	Clusters       []kubeconfigCluster `json:"clusters"`
+	Users          []kubeconfigUser    `json:"users,omitempty"`
+	Contexts       []kubeconfigContext `json:"contexts"`
+	CurrentContext string              `json:"current-context"`
+}
+
+type kubeconfigCluster struct {
+	Name    string `json:"name"`
+	Cluster struct {
+		Server               string `json:"server"`
+		TLSServerName        string `json:"tls-server-name,omitempty"`
+		CertificateAuthority string `json:"certificate-authority,omitempty"`
+	} `json:"cluster"`
+}
+
+type kubeconfigUser struct {
+	Name string `json:"name"`
+	User struct {
+		Token string `json:"token,omitempty"`
+	} `json:"user"`
+}
+
+type kubeconfigContext struct {
+	Name    string `json:"name"`
+	Context struct {
+		Cluster   string `json:"cluster"`
+		User      string `json:"user,omitempty"`
+		Namespace string `json:"namespace,omitempty"`
+	} `json:"context"`
+}
+
+// newKubernetesCommand writes a temporary kubeconfig pointing to the local
+// proxy and returns the configured kubernetes client with KUBECONFIG set. The
+// kubeconfig is removed when the session terminates
+func (s *SessionInfo) newKubernetesCommand() (*exec.Cmd, error) {
+	file, err := s.writeKubeconfig()
+	if err != nil {
+		return nil, err
+	}
+	s.onTerminate(func() { os.Remove(file) })
+
+	kubeconfigs := []string{file}
+	if current := os.Getenv("KUBECONFIG"); current != "" {
+		kubeconfigs = append(kubeconfigs, current)
+	} else if home, err := os.UserHomeDir(); err == nil {
+		// keep the users from the default kubeconfig available
+		kubeconfigs = append(kubeconfigs, path.Join(home, ".kube", "config"))
+	}
+
+	args := s.target.settings.KubernetesCommand
+	if len(args) == 0 {
+		args = []string{userShell()}
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Env = append(os.Environ(), s.env()...)
+	cmd.Env = append(cmd.Env, "KUBECONFIG="+strings.Join(kubeconfigs, string(os.PathListSeparator)))
+
+	return cmd, nil
+}
+
+// kubernetesServerName returns the host the API server certificate was
+// issued for, the target address or the host the session was authorized for
+// when the target uses host sources
+func (s *SessionInfo) kubernetesServerName() (string, error) {
+	if s.target.target.Address != "" {
+		return s.target.target.Address, nil
+	}
+
+	if endpoint, err := url.Parse(s.Endpoint); err == nil && endpoint.Hostname() != "" {
+		return endpoint.Hostname(), nil
+	}
+
+	return "", fmt.Errorf("the kubernetes server name is unknown, target %s has no address and session %s no endpoint", s.target.target.Name, s.SessionId)
+}
+
+func (s *SessionInfo) writeKubeconfig() (string, error) {
+	t := s.target
+	name := "boundary-" + unsafeNameChars.ReplaceAllString(strings.ToLower(t.target.Name), "-")
+
+	serverName, err := s.kubernetesServerName()
+	if err != nil {
+		return "", err
+	}
+
+	cluster := kubeconfigCluster{Name: name}
+	cluster.Cluster.Server = "https://" + net.JoinHostPort(s.Address, strconv.Itoa(s.Port))
+	// the certificate was issued for the real address, not for the proxy
+	cluster.Cluster.TLSServerName = serverName
+	cluster.Cluster.CertificateAuthority = t.settings.KubernetesCAFile
+
+	context := kubeconfigContext{Name: name}
+	context.Context.Cluster = name
+	context.Context.User = t.settings.KubernetesUser
+
+	config := kubeconfig{
+		ApiVersion:     "v1",
+		Kind:           "Config",
+		Clusters:       []kubeconfigCluster{cluster},
+		Contexts:       []kubeconfigContext{context},
+		CurrentContext: name,
+	}
+
+	// use brokered credentials, e.g. from the vault kubernetes secrets engine
+	for _, credential := range s.Credentials {
+		token, _ := credential.Secret.Decoded["service_account_token"].(string)
+		if token == "" {
+			token, _ = credential.Secret.Decoded["token"].(string)
+		}
+		if token == "" {
+			continue
+		}
+
+		user := kubeconfigUser{Name: name}
+		user.User.Token = token
+		config.Users = []kubeconfigUser{user}
+		config.Contexts[0].Context.User = name
+		config.Contexts[0].Context.Namespace, _ = credential.Secret.Decoded["service_account_namespace"].(string)
+		break
+	}
+
+	f, err := os.CreateTemp("", "boundary-fuzzy-*.kubeconfig")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	// kubeconfig files are YAML, which is a superset of JSON
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(config); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("could not write kubeconfig: %w", err)
+	}
+
+	return f.Name(), nil
+}
+
+// userShell returns the shell of the user
+func userShell() string {
+	if shell := os.Getenv("SHELL"); shell != "" {
+		return shell
+	}
+
+	if runtime.GOOS == "windows" {
+		return os.Getenv("COMSPEC")
+	}
+
+	return "/bin/sh"
+}
@@ -0,0 +1,42 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/hashicorp/boundary/api/targets"
+)
+
+func TestKubernetesServerName(t *testing.T) {
+	tests := []struct {
+		name     string
+		address  string
+		endpoint string
+		want     string
+		wantErr  bool
+	}{
+		{name: "target address", address: "k8s.example.com", endpoint: "tcp://10.0.0.1:6443", want: "k8s.example.com"},
+		{name: "host source", endpoint: "tcp://k8s.internal:6443", want: "k8s.internal"},
+		{name: "host source ipv6", endpoint: "tcp://[fd00::1]:6443", want: "fd00::1"},
+		{name: "unknown", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &SessionInfo{
+				target:   &Target{target: &targets.Target{Name: "k8s", Address: tt.address}},
+				Endpoint: tt.endpoint,
+			}
+
+			got, err := session.kubernetesServerName()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("kubernetesServerName() = %q, want an error", got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("kubernetesServerName() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
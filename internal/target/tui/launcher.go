@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/AndreZiviani/boundary-fuzzy/internal/config"
+	"github.com/AndreZiviani/boundary-fuzzy/internal/run"
+)
+
+func (t *Target) defaultPort() int {
+	if port, ok := t.target.Attributes["default_port"].(float64); ok {
+		return int(port)
+	}
+
+	return 0
+}
+
+// launcher returns the configured launcher for this target, if any
+func (t *Target) launcher() (config.Launcher, bool) {
+	return t.settings.LauncherFor(t.target.Scope.Name, t.target.Name, t.defaultPort())
+}
+
+func (t *Target) isBackground() bool {
+	launcher, ok := t.launcher()
+	return ok && launcher.Background
+}
+
+// background returns the most recent session of the target running a
+// background process, if any
+func (t *Target) background() *SessionInfo {
+	sessions := t.Sessions()
+	for i := len(sessions) - 1; i >= 0; i-- {
+		if sessions[i].Task() != nil {
+			return sessions[i]
+		}
+	}
+
+	return nil
+}
+
+func (s *SessionInfo) newLauncherCommand(launcher config.Launcher) *exec.Cmd {
+	replacer := s.templateReplacer()
+	args := make([]string, len(launcher.Command))
+	for i, arg := range launcher.Command {
+		args[i] = replacer.Replace(arg)
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Env = append(os.Environ(), s.env()...)
+
+	return cmd
+}
+
+// startBackground starts the launcher of the session in the background, the
+// process and the session are stopped together
+func (s *SessionInfo) startBackground(cmd *exec.Cmd) error {
+	task, err := run.Start(cmd, run.DefaultOutputLines)
+	if err != nil {
+		s.target.sessions.Remove(s)
+		s.Terminate()
+		return err
+	}
+	if !s.setTask(task) {
+		task.Stop(run.DefaultGracePeriod)
+		return fmt.Errorf("session %s ended before %s started", s.SessionId, task.Command)
+	}
+
+	go s.supervise(task, time.Now())
+
+	return nil
+}
+
+// supervise terminates the session when the background process exits, the
+// session stops the process when it ends for any other reason
+func (s *SessionInfo) supervise(task *run.Task, start time.Time) {
+	select {
+	case <-task.Done():
+		s.terminate(sessionClosed)
+	case <-s.ctx.Done():
+	}
+
+	<-task.Done()
+	s.logCommand(task.Cmd, start, "", task.Err())
+}
@@ -0,0 +1,225 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/AndreZiviani/boundary-fuzzy/internal/auth"
+	"github.com/AndreZiviani/boundary-fuzzy/internal/client"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hashicorp/boundary/api"
+	"github.com/hashicorp/boundary/api/authtokens"
+)
+
+const tokenTickInterval = time.Second
+
+// errUnauthenticated is returned when there is no valid token to use
+var errUnauthenticated = errors.New("not logged in or the token expired")
+
+// isUnauthenticated reports if err means we must log in again
+func isUnauthenticated(err error) bool {
+	return errors.Is(err, errUnauthenticated) || client.IsUnauthenticated(err)
+}
+
+// msgTokenTick updates the token countdown and checks if the token is about
+// to expire
+type msgTokenTick struct{}
+
+// msgLogin is sent when the login started from the TUI finishes
+type msgLogin struct {
+	boundaryClient *api.Client
+	boundaryToken  *authtokens.AuthToken
+	err            error
+}
+
+func tokenTickCmd() tea.Cmd {
+	return tea.Tick(tokenTickInterval, func(time.Time) tea.Msg { return msgTokenTick{} })
+}
+
+// tokenRemaining returns how long the token is still valid, ok is false if
+// the expiration is unknown
+func (t *tui) tokenRemaining() (time.Duration, bool) {
+	if t.boundaryToken == nil || t.boundaryToken.ExpirationTime.IsZero() {
+		return 0, false
+	}
+
+	return time.Until(t.boundaryToken.ExpirationTime), true
+}
+
+// tokenCountdown returns the time left before the token expires, styled
+// according to how close it is
+func (t *tui) tokenCountdown() string {
+	remaining, ok := t.tokenRemaining()
+	switch {
+	case !ok:
+		return ""
+	case remaining <= 0:
+		return errorStyle("token expired")
+	}
+
+	if remaining < t.config.Settings.TokenExpiryWarning.Duration {
+		return warningStyle(fmt.Sprintf("token expires in %s", remaining.Truncate(time.Second)))
+	}
+
+	// seconds are only worth showing when the token is about to expire
+	return choiceStyle.Render(fmt.Sprintf("token expires in %s", strings.TrimSuffix(remaining.Truncate(time.Minute).String(), "0s")))
+}
+
+// tokenTick asks the user to log in again once per token when it is about to
+// expire, the prompt waits until the user is back at the tabs
+func (t tui) tokenTick() (tea.Model, tea.Cmd) {
+	remaining, ok := t.tokenRemaining()
+	if !ok || remaining > t.config.Settings.TokenExpiryWarning.Duration || t.loginPrompted == t.boundaryToken.Id {
+		return t, tokenTickCmd()
+	}
+
+	switch t.state {
+	case targetsView, connectedView, favoriteView:
+		t.loginPrompted = t.boundaryToken.Id
+		t.SetState(loginView)
+	}
+
+	return t, tokenTickCmd()
+}
+
+func (t tui) loginUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		return t.resize(size)
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return t, nil
+	}
+
+	t.state = t.previousState
+
+	// For simplicity's sake, we'll treat any key besides "y" as "no"
+	if keyMsg.String() != "y" {
+		return t, nil
+	}
+
+	// loginCmd changes t, it must run before t is returned
+	cmd := t.loginCmd()
+	return t, cmd
+}
+
+func (t tui) HandleLoginView() string {
+	question := "Your Boundary token expires soon, log in again now?"
+	if remaining, _ := t.tokenRemaining(); remaining <= 0 {
+		question = "Your Boundary token expired, log in again now?"
+	}
+
+	text := alertViewStyle.Render(
+		lipgloss.JoinVertical(
+			lipgloss.Left,
+			fmt.Sprintf("%s %s", question, choiceStyle.Render("[y/N]")),
+			t.tokenCountdown(),
+		),
+	)
+
+	paddingHeight := (t.height - lipgloss.Height(text)) / 2
+	paddingWidth := (t.width - lipgloss.Width(text)) / 2
+
+	return lipgloss.NewStyle().Padding(
+		max(0, paddingHeight-1),
+		max(0, paddingWidth),
+		0,
+	).Render(text)
+}
+
+// loginCommand runs the login flow with the terminal released by the TUI
+type loginCommand struct {
+	ctx    context.Context
+	result msgLogin
+}
+
+func (l *loginCommand) Run() error {
+	if _, err := auth.Login(l.ctx, true); err != nil {
+		l.result.err = err
+		return err
+	}
+
+	l.result.boundaryClient, l.result.boundaryToken, l.result.err = client.NewBoundaryClient(l.ctx)
+	if l.result.err == nil && l.result.boundaryToken == nil {
+		l.result.err = fmt.Errorf("could not read the new token from the keyring")
+	}
+
+	return l.result.err
+}
+
+func (l *loginCommand) SetStdin(io.Reader)  {}
+func (l *loginCommand) SetStdout(io.Writer) {}
+func (l *loginCommand) SetStderr(io.Writer) {}
+
+// loginCmd suspends the TUI while the user logs in, only one login runs at
+// a time
+func (t *tui) loginCmd() tea.Cmd {
+	if t.loggingIn {
+		return nil
+	}
+	t.loggingIn = true
+
+	login := &loginCommand{ctx: t.ctx}
+	return tea.Exec(login, func(error) tea.Msg { return login.result })
+}
+
+// reauthenticate logs in again when an operation failed because the token
+// is no longer valid
+func (t tui) reauthenticate(err error) (tea.Model, tea.Cmd) {
+	model, notify := t.notify(msgNotify{level: levelError, text: err.Error()})
+	t = model.(tui)
+
+	if t.loggingIn {
+		return t, notify
+	}
+
+	cmd := t.loginCmd()
+	return t, tea.Sequence(notify, cmd)
+}
+
+// login uses the new token for every target and session and reloads the
+// targets with it, the sessions stay connected
+func (t tui) login(msg msgLogin) (tea.Model, tea.Cmd) {
+	t.loggingIn = false
+
+	// not through msgError, a rejected login would start another login
+	if msg.err != nil {
+		return t.notify(msgNotify{level: levelError, text: newOpError("log in", msg.err).Error()})
+	}
+
+	t.setClient(msg.boundaryClient, msg.boundaryToken)
+	t.updateClients()
+
+	return t, tea.Batch(
+		notifyCmd(levelInfo, "logged in, the token expires at %s", msg.boundaryToken.ExpirationTime.Local().Format(time.RFC3339)),
+		t.refreshTargetsCmd(),
+	)
+}
+
+// updateClients replaces the clients of the targets and sessions that were
+// created with the previous token
+func (t *tui) updateClients() {
+	update := func(target *Target) {
+		target.targetClient = t.targetsClient
+		target.sessionsClient = t.sessionsClient
+	}
+
+	for _, tab := range t.tabs {
+		for _, item := range tab.Items() {
+			if target := itemTarget(item); target != nil {
+				update(target)
+			}
+		}
+	}
+
+	for _, session := range t.sessions.All() {
+		update(session.target)
+		session.setClient(t.sessionsClient)
+	}
+}
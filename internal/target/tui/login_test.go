@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/boundary/api"
+)
+
+// runCmd returns the messages produced by cmd and the commands it batches,
+// commands that take longer than a moment, like toast timers, are skipped
+func runCmd(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+
+	result := make(chan tea.Msg, 1)
+	go func() { result <- cmd() }()
+
+	var msg tea.Msg
+	select {
+	case msg = <-result:
+	case <-time.After(100 * time.Millisecond):
+		return nil
+	}
+
+	// batched and sequenced commands are slices of commands
+	if value := reflect.ValueOf(msg); value.Kind() == reflect.Slice && value.Type().Elem() == reflect.TypeOf(cmd) {
+		msgs := []tea.Msg{}
+		for i := 0; i < value.Len(); i++ {
+			msgs = append(msgs, runCmd(value.Index(i).Interface().(tea.Cmd))...)
+		}
+		return msgs
+	}
+
+	return []tea.Msg{msg}
+}
+
+func isExec(msg tea.Msg) bool {
+	return fmt.Sprintf("%T", msg) == "tea.execMsg"
+}
+
+func TestLoginFailureDoesNotLoginAgain(t *testing.T) {
+	var model tea.Model = tui{loggingIn: true}
+	model, cmd := model.Update(msgLogin{err: api.ErrUnauthorized})
+
+	if model.(tui).loggingIn {
+		t.Fatal("still logging in after the login failed")
+	}
+	if len(model.(tui).notifications) != 1 {
+		t.Fatalf("got %d notifications, want the login error", len(model.(tui).notifications))
+	}
+
+	// follow the messages for a few rounds, a login loop would show up as
+	// another exec
+	for range 3 {
+		msgs := runCmd(cmd)
+		if len(msgs) == 0 {
+			return
+		}
+
+		cmds := []tea.Cmd{}
+		for _, msg := range msgs {
+			if isExec(msg) {
+				t.Fatal("a failed login started another login")
+			}
+
+			var next tea.Cmd
+			model, next = model.Update(msg)
+			cmds = append(cmds, next)
+		}
+		cmd = tea.Batch(cmds...)
+	}
+}
+
+func TestUnauthenticatedErrorLogsIn(t *testing.T) {
+	var model tea.Model = tui{}
+	_, cmd := model.Update(msgError{err: api.ErrUnauthorized})
+
+	for _, msg := range runCmd(cmd) {
+		if isExec(msg) {
+			return
+		}
+	}
+	t.Fatal("an unauthenticated error did not start a login")
+}
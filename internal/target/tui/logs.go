@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const logRefreshInterval = 500 * time.Millisecond
+
+type msgLogs struct {
+	session *SessionInfo
+}
+
+// msgLogTick refreshes the log pane, id identifies the pane that requested it
+type msgLogTick struct {
+	id int
+}
+
+func (t tui) logTickCmd() tea.Cmd {
+	id := t.logTick
+	return tea.Tick(logRefreshInterval, func(time.Time) tea.Msg { return msgLogTick{id: id} })
+}
+
+func (t tui) startLogs(msg msgLogs) (tea.Model, tea.Cmd) {
+	t.logSession = msg.session
+	t.logTick++
+	t.logViewport = viewport.New(0, 0)
+	t.resizeLogs()
+	t.refreshLogs()
+	t.logViewport.GotoBottom()
+	t.SetState(logView)
+
+	return t, t.logTickCmd()
+}
+
+func (t *tui) resizeLogs() {
+	// remove tabs, borders, title, footer and status bar from the window size
+	t.logViewport.Width = max(0, t.width-2)
+	t.logViewport.Height = max(0, t.height-7)
+}
+
+func (t *tui) refreshLogs() {
+	// keep following the output unless the user scrolled up
+	follow := t.logViewport.AtBottom()
+	t.logViewport.SetContent(strings.Join(t.logSession.Task().Output.Lines(), "\n"))
+	if follow {
+		t.logViewport.GotoBottom()
+	}
+}
+
+func (t tui) logUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		t.width = msg.Width
+		t.height = msg.Height
+		t.resizeLogs()
+
+		return t, t.UpdateTabs(t.tabsSize(msg))
+
+	case msgLogTick:
+		if msg.id != t.logTick {
+			return t, nil
+		}
+		t.refreshLogs()
+		return t, t.logTickCmd()
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			t.logSession = nil
+			t.logTick++
+			t.state = t.previousState
+			return t, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	t.logViewport, cmd = t.logViewport.Update(msg)
+	return t, cmd
+}
+
+func (t tui) HandleLogView() string {
+	session := t.logSession
+	border := environmentColor(session.target)
+
+	title := fmt.Sprintf("%s (%d): %s", session.target.target.Name, session.Port, session.Task().Status())
+	footer := choiceStyle.Render(fmt.Sprintf("%3.f%%  ↑/↓ scroll  esc back", t.logViewport.ScrollPercent()*100))
+
+	return t.RenderPane(connectedView, border, title, t.logViewport.View(), footer)
+}
@@ -3,20 +3,25 @@ package tui
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 
-	"github.com/charmbracelet/bubbles/key"
+	"github.com/AndreZiviani/boundary-fuzzy/internal/config"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/hashicorp/boundary/api"
 	"github.com/hashicorp/boundary/api/authtokens"
 	"github.com/hashicorp/boundary/api/sessions"
 	"github.com/hashicorp/boundary/api/targets"
+	"github.com/pkg/browser"
 )
 
 type TuiInput struct {
 	BoundaryClient  *api.Client
 	BoundaryToken   *authtokens.AuthToken
+	Config          config.Config
+	KeyBindings     keyBindings
 	Tabs            []*list.Model
 	TargetKeyMap    *DelegateKeyMap
 	ConnectedKeyMap *DelegateKeyMap
@@ -32,44 +37,51 @@ func newTui(ctx context.Context, input TuiInput) tui {
 		targetsClient:   targets.NewClient(input.BoundaryClient),
 		sessionsClient:  sessions.NewClient(input.BoundaryClient),
 		boundaryToken:   input.BoundaryToken,
+		config:          input.Config,
+		sessions:        newSessionManager(),
+		keyBindings:     input.KeyBindings,
 		tabs:            input.Tabs,
 		targetKeyMap:    input.TargetKeyMap,
 		connectedKeyMap: input.ConnectedKeyMap,
 		favoriteKeyMap:  input.FavoriteKeyMap,
+		spinner:         spinner.New(spinner.WithSpinner(spinner.Dot)),
 	}
 	return m
 }
 
-func Tui(ctx context.Context, targetListResult *targets.TargetListResult, boundaryClient *api.Client, boundaryToken *authtokens.AuthToken) {
+// Tui runs the target selector, with useCache the targets from the last run
+// are shown right away and refreshed in the background
+func Tui(ctx context.Context, boundaryClient *api.Client, boundaryToken *authtokens.AuthToken, useCache bool) {
+	cfg, err := config.NewConfig()
+	if err != nil {
+		fmt.Println("Error loading config:", err)
+		os.Exit(1)
+	}
+
+	if err := loadTheme(cfg.Settings); err != nil {
+		fmt.Println("Error loading theme:", err)
+		os.Exit(1)
+	}
+
+	bindings, err := newKeyBindings(cfg.Settings)
+	if err != nil {
+		fmt.Println("Error loading key bindings:", err)
+		os.Exit(1)
+	}
+
 	tuiTargets := make([]list.Item, 0)
 
-	targetList, targetKeyMap := NewList(targetsTabName, targetsView, tuiTargets, map[string]key.Binding{
-		bindingShell.name:    bindingShell.binding,
-		bindingConnect.name:  bindingConnect.binding,
-		bindingFavorite.name: bindingFavorite.binding,
-		bindingInfo.name:     bindingInfo.binding,
-		bindingRefresh.name:  bindingRefresh.binding,
-	}, TargetsUpdate, nil)
-
-	connectedList, connectedKeyMap := NewList(connectedTabName, connectedView, []list.Item{}, map[string]key.Binding{
-		bindingDisconnect.name: bindingDisconnect.binding,
-		bindingReconnect.name:  bindingReconnect.binding,
-		bindingInfo.name:       bindingInfo.binding,
-		bindingFavorite.name:   bindingFavorite.binding,
-	}, ConnectedUpdate, nil)
-
-	favoriteList, favoriteKeyMap := NewList(favoritesTabName, favoriteView, []list.Item{}, map[string]key.Binding{
-		bindingShell.name:        bindingShell.binding,
-		bindingDelete.name:       bindingDelete.binding,
-		bindingConnect.name:      bindingConnect.binding,
-		bindingFavoriteUp.name:   bindingFavoriteUp.binding,
-		bindingFavoriteDown.name: bindingFavoriteDown.binding,
-		bindingInfo.name:         bindingInfo.binding,
-	}, FavoritesUpdate, nil)
+	targetList, targetKeyMap := NewList(targetsTabName, targetsView, tuiTargets, bindings[targetsBindings], TargetsUpdate, nil)
+
+	connectedList, connectedKeyMap := NewList(connectedTabName, connectedView, []list.Item{}, bindings[connectedBindings], ConnectedUpdate, nil)
+
+	favoriteList, favoriteKeyMap := NewList(favoritesTabName, favoriteView, []list.Item{}, bindings[favoriteBindings], FavoritesUpdate, nil)
 
 	t := newTui(ctx, TuiInput{
 		BoundaryClient: boundaryClient,
 		BoundaryToken:  boundaryToken,
+		Config:         cfg,
+		KeyBindings:    bindings,
 
 		Tabs:            []*list.Model{&targetList, &connectedList, &favoriteList},
 		TargetKeyMap:    targetKeyMap,
@@ -77,13 +89,20 @@ func Tui(ctx context.Context, targetListResult *targets.TargetListResult, bounda
 		FavoriteKeyMap:  favoriteKeyMap,
 	})
 
-	err := t.refreshTargets()
+	if !useCache || !t.loadCachedTargets() {
+		err = t.refreshTargets()
+	}
 	if err != nil {
 		fmt.Println("Error running program:", err)
 		os.Exit(1)
 	}
 
+	// the browser output would corrupt the screen
+	browser.Stdout = io.Discard
+	browser.Stderr = io.Discard
+
 	p := tea.NewProgram(t, tea.WithAltScreen(), tea.WithFilter(filter), tea.WithMouseCellMotion())
+	t.sessions.SetSender(p.Send)
 
 	if _, err := p.Run(); err != nil {
 		fmt.Println("Error running program:", err)
@@ -91,26 +110,14 @@ func Tui(ctx context.Context, targetListResult *targets.TargetListResult, bounda
 	}
 }
 
-func NewList(name string, view sessionState, items []list.Item, bindings map[string]key.Binding, updater delegateUpdateFunc, renderer delegateRenderFunc) (list.Model, *DelegateKeyMap) {
+func NewList(name string, view sessionState, items []list.Item, bindings []binding, updater delegateUpdateFunc, renderer delegateRenderFunc) (list.Model, *DelegateKeyMap) {
 	delegate, keyMap := NewDelegate(bindings, view, updater, renderer)
 	customList := list.New(items, delegate, 0, 0)
 	customList.Title = name
 	customList.AdditionalShortHelpKeys = keyMap.ShortHelp
 	customList.AdditionalFullHelpKeys = keyMap.ShortHelp
 	customList.SetShowTitle(false)
-	customList.DisableQuitKeybindings()
-
-	// remove some keys from the default keymap
-	PrevPage := key.NewBinding(
-		key.WithKeys("left", "h", "pgup"),
-		key.WithHelp("←/h/pgup", "prev page"),
-	)
-	NextPage := key.NewBinding(
-		key.WithKeys("right", "l", "pgdown"),
-		key.WithHelp("→/l/pgdn", "next page"),
-	)
-	customList.KeyMap.PrevPage = PrevPage
-	customList.KeyMap.NextPage = NextPage
+	customList.KeyMap = listKeyMap()
 
 	return customList, keyMap
 }
@@ -0,0 +1,226 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/AndreZiviani/boundary-fuzzy/internal/run"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type sessionStatus int
+
+const (
+	sessionActive sessionStatus = iota
+	// sessionClosed means the proxy or the background process stopped, e.g.
+	// because the connection limit was reached
+	sessionClosed
+	sessionExpired
+	// sessionTerminated means the session was terminated by the user
+	sessionTerminated
+)
+
+func (s sessionStatus) String() string {
+	switch s {
+	case sessionActive:
+		return "connected"
+	case sessionClosed:
+		return "closed"
+	case sessionExpired:
+		return "expired"
+	case sessionTerminated:
+		return "terminated"
+	}
+
+	return ""
+}
+
+// msgSessionChanged is sent by the session manager when a session ends
+// without being terminated by the user
+type msgSessionChanged struct {
+	session *SessionInfo
+	status  sessionStatus
+}
+
+// msgConnectionsLeft is sent by the session manager when a connection to a
+// session with a connection limit is made
+type msgConnectionsLeft struct {
+	session *SessionInfo
+	left    int32
+}
+
+// sessionManager owns the sessions of every target, it is safe for concurrent
+// use and reports sessions that end on their own to the program
+type sessionManager struct {
+	mu       sync.Mutex
+	sessions []*SessionInfo
+	send     func(tea.Msg)
+}
+
+func newSessionManager() *sessionManager {
+	return &sessionManager{}
+}
+
+// SetSender sets the function used to report session changes, usually
+// tea.Program.Send
+func (m *sessionManager) SetSender(send func(tea.Msg)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.send = send
+}
+
+// Add registers a session and watches it until it ends
+func (m *sessionManager) Add(session *SessionInfo) {
+	m.mu.Lock()
+	m.sessions = append(m.sessions, session)
+	m.mu.Unlock()
+
+	go m.watch(session)
+}
+
+// Remove forgets a session, it does not terminate it
+func (m *sessionManager) Remove(session *SessionInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions = slices.DeleteFunc(m.sessions, func(s *SessionInfo) bool { return s == session })
+}
+
+// ForTarget returns the sessions of a target, oldest first
+func (m *sessionManager) ForTarget(targetId string) []*SessionInfo {
+	if m == nil {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sessions := []*SessionInfo{}
+	for _, session := range m.sessions {
+		if session.target.target.Id == targetId {
+			sessions = append(sessions, session)
+		}
+	}
+
+	return sessions
+}
+
+// All returns every session, oldest first
+func (m *sessionManager) All() []*SessionInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(m.sessions)
+}
+
+// Active returns the number of sessions that are still connected
+func (m *sessionManager) Active() int {
+	active := 0
+	for _, session := range m.All() {
+		if session.IsActive() {
+			active++
+		}
+	}
+
+	return active
+}
+
+// TerminateAll terminates and forgets every session
+func (m *sessionManager) TerminateAll() error {
+	m.mu.Lock()
+	sessions := m.sessions
+	m.sessions = nil
+	m.mu.Unlock()
+
+	errs := make([]error, len(sessions))
+	var wg sync.WaitGroup
+	for i, session := range sessions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = session.Terminate()
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// watch reports the connections used by the session and waits for it to end,
+// the end is reported unless the session was terminated by the user
+func (m *sessionManager) watch(session *SessionInfo) {
+	expired := time.NewTimer(time.Until(session.Expiration))
+	defer expired.Stop()
+
+	status := sessionClosed
+wait:
+	for {
+		select {
+		case <-session.changed:
+			m.emit(msgConnectionsLeft{session: session, left: session.ConnectionsLeft()})
+			continue
+		case <-session.clientProxyCloseCh:
+		case <-session.ctx.Done():
+		case <-expired.C:
+			status = sessionExpired
+		}
+		break wait
+	}
+
+	// the status is already set if something else ended the session
+	session.end(status)
+	status = session.Status()
+	if status == sessionTerminated {
+		return
+	}
+
+	m.emit(msgSessionChanged{session: session, status: status})
+}
+
+func (m *sessionManager) emit(msg tea.Msg) {
+	m.mu.Lock()
+	send := m.send
+	m.mu.Unlock()
+
+	if send != nil {
+		send(msg)
+	}
+}
+
+func (t tui) sessionChanged(msg msgSessionChanged) (tea.Model, tea.Cmd) {
+	session := msg.session
+	text := fmt.Sprintf("session to %s on port %d %s", session.target.target.Name, session.Port, msg.status)
+	if task := session.Task(); task != nil && task.State() != run.Stopped {
+		text = fmt.Sprintf("%s: background process %s", text, task.Status())
+	}
+
+	return t.notify(msgNotify{level: levelWarning, text: text})
+}
+
+// connectionsLeft warns when a session used its last connection and
+// authorizes a new session on the same port if the settings allow it, the
+// spent session keeps serving its open connections until they are closed
+func (t tui) connectionsLeft(msg msgConnectionsLeft) (tea.Model, tea.Cmd) {
+	session := msg.session
+	if msg.left != 0 || !session.limited() {
+		return t, nil
+	}
+
+	target := session.target
+	if !t.config.Settings.ReauthorizeOnConnectionLimit {
+		return t.notify(msgNotify{
+			level: levelWarning,
+			text:  fmt.Sprintf("%s used all of its %d connections, reconnect it to authorize a new session on port %d", target.target.Name, session.ConnectionLimit, session.Port),
+		})
+	}
+
+	if target.progress.Busy() {
+		return t, nil
+	}
+
+	return t, tea.Sequence(
+		notifyCmd(levelInfo, "%s used all of its %d connections, authorizing a new session on port %d", target.target.Name, session.ConnectionLimit, session.Port),
+		target.reconnect(session, session.Port),
+	)
+}
@@ -0,0 +1,243 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/boundary/api"
+	"github.com/hashicorp/boundary/api/sessions"
+	"github.com/hashicorp/boundary/api/targets"
+)
+
+// recorder collects the messages sent by the session manager
+type recorder struct {
+	mu   sync.Mutex
+	msgs []tea.Msg
+}
+
+func (r *recorder) send(msg tea.Msg) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.msgs = append(r.msgs, msg)
+}
+
+func (r *recorder) changes(session *SessionInfo) []sessionStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	statuses := []sessionStatus{}
+	for _, msg := range r.msgs {
+		if changed, ok := msg.(msgSessionChanged); ok && changed.session == session {
+			statuses = append(statuses, changed.status)
+		}
+	}
+	return statuses
+}
+
+// waitChanges waits for the session changes to be reported and for any
+// extra report that should not be sent
+func (r *recorder) waitChanges(t *testing.T, session *SessionInfo, want int) []sessionStatus {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(r.changes(session)) < want && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	return r.changes(session)
+}
+
+// newSessionsClient returns a client for a controller where every session
+// was already removed
+func newSessionsClient(t *testing.T) *sessions.Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"kind":"NotFound","message":"session not found"}`)
+	}))
+	t.Cleanup(server.Close)
+
+	boundaryClient, err := api.NewClient(&api.Config{Addr: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return sessions.NewClient(boundaryClient)
+}
+
+func newManagedSession(sessionsClient *sessions.Client, targetId string, expiration time.Time) *SessionInfo {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &SessionInfo{
+		ctx:                ctx,
+		cancel:             cancel,
+		clientProxyCloseCh: make(chan struct{}),
+		target:             &Target{target: &targets.Target{Id: targetId, Name: targetId}, progress: &progress{}},
+		changed:            make(chan struct{}, 1),
+		sessionClient:      sessionsClient,
+		connectionsLeft:    -1,
+		SessionId:          "s_" + targetId,
+		Expiration:         expiration,
+	}
+}
+
+func TestSessionManagerConcurrentUse(t *testing.T) {
+	sessionsClient := newSessionsClient(t)
+	recorder := &recorder{}
+	manager := newSessionManager()
+	manager.SetSender(recorder.send)
+
+	const workers = 20
+	var wg sync.WaitGroup
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			targetId := fmt.Sprintf("ttcp_%d", i%4)
+			session := newManagedSession(sessionsClient, targetId, time.Now().Add(time.Hour))
+			manager.Add(session)
+
+			for _, s := range manager.ForTarget(targetId) {
+				if s.target.target.Id != targetId {
+					t.Errorf("ForTarget(%s) returned a session of %s", targetId, s.target.target.Id)
+				}
+			}
+			_ = manager.All()
+			_ = manager.Active()
+
+			if i%2 == 0 {
+				manager.Remove(session)
+				if err := session.Terminate(); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+
+	// terminate while sessions are still being added
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := manager.TerminateAll(); err != nil {
+			t.Error(err)
+		}
+	}()
+	wg.Wait()
+
+	if err := manager.TerminateAll(); err != nil {
+		t.Fatal(err)
+	}
+	if sessions := manager.All(); len(sessions) != 0 {
+		t.Fatalf("%d sessions left after TerminateAll", len(sessions))
+	}
+
+	// sessions terminated by the user are not reported
+	time.Sleep(50 * time.Millisecond)
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	for _, msg := range recorder.msgs {
+		if changed, ok := msg.(msgSessionChanged); ok {
+			t.Errorf("terminated session reported as %s", changed.status)
+		}
+	}
+}
+
+func TestSessionManagerReportsEachChangeOnce(t *testing.T) {
+	sessionsClient := newSessionsClient(t)
+
+	tests := []struct {
+		name       string
+		expiration time.Duration
+		end        func(s *SessionInfo)
+		want       []sessionStatus
+	}{
+		{
+			name:       "proxy closed",
+			expiration: time.Hour,
+			end:        func(s *SessionInfo) { close(s.clientProxyCloseCh) },
+			want:       []sessionStatus{sessionClosed},
+		},
+		{
+			name:       "context canceled",
+			expiration: time.Hour,
+			end:        func(s *SessionInfo) { s.cancel() },
+			want:       []sessionStatus{sessionClosed},
+		},
+		{
+			name:       "expired",
+			expiration: 10 * time.Millisecond,
+			end:        func(s *SessionInfo) {},
+			want:       []sessionStatus{sessionExpired},
+		},
+		{
+			name:       "ended twice",
+			expiration: time.Hour,
+			end: func(s *SessionInfo) {
+				s.end(sessionClosed)
+				s.end(sessionExpired)
+				close(s.clientProxyCloseCh)
+			},
+			want: []sessionStatus{sessionClosed},
+		},
+		{
+			name:       "terminated",
+			expiration: time.Hour,
+			end:        func(s *SessionInfo) { _ = s.Terminate() },
+			want:       []sessionStatus{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &recorder{}
+			manager := newSessionManager()
+			manager.SetSender(recorder.send)
+
+			session := newManagedSession(sessionsClient, "ttcp_1", time.Now().Add(tt.expiration))
+			manager.Add(session)
+			tt.end(session)
+
+			got := recorder.waitChanges(t, session, len(tt.want))
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("reported %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionManagerReportsConnectionsLeft(t *testing.T) {
+	recorder := &recorder{}
+	manager := newSessionManager()
+	manager.SetSender(recorder.send)
+
+	session := newManagedSession(newSessionsClient(t), "ttcp_1", time.Now().Add(time.Hour))
+	session.ConnectionLimit = 2
+	manager.Add(session)
+
+	session.setConnectionsLeft(0)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		recorder.mu.Lock()
+		for _, msg := range recorder.msgs {
+			if left, ok := msg.(msgConnectionsLeft); ok && left.session == session && left.left == 0 {
+				recorder.mu.Unlock()
+				_ = manager.TerminateAll()
+				return
+			}
+		}
+		recorder.mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatal("connections left were not reported")
+}
@@ -11,8 +11,13 @@ type msgError struct {
 	err error
 }
 
+// msgConnect adds a target to the connected tab, it is shown as connecting
+// until the message with its session replaces it
 type msgConnect struct {
-	target *Target
+	target  *Target
+	session *SessionInfo
+	// replaces is the session that was reconnected, if any
+	replaces *SessionInfo
 }
 
 type msgFavorite struct {
@@ -20,14 +25,21 @@ type msgFavorite struct {
 }
 
 type msgInfo struct {
-	target *Target
+	text string
 }
 
 type msgRefresh struct {
 }
 
+type msgConfirm struct {
+	target *Target
+	action func() tea.Cmd
+}
+
 func (t tui) messageUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg.(type) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		return t.resize(msg)
 	case tea.KeyMsg:
 		t.message = ""
 		t.state = t.previousState
@@ -47,8 +59,8 @@ func (t tui) HandleMessageView() string {
 	paddingWidth := (t.width - lipgloss.Width(text)) / 2
 
 	return lipgloss.NewStyle().Padding(
-		paddingHeight-1,
-		paddingWidth,
+		max(0, paddingHeight-1),
+		max(0, paddingWidth),
 		0,
 	).Render(text)
 }
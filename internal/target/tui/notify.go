@@ -0,0 +1,181 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// maxNotifications is the number of notifications kept in the history
+	maxNotifications = 200
+
+	infoToastDuration  = 5 * time.Second
+	errorToastDuration = 10 * time.Second
+)
+
+type level int
+
+const (
+	levelInfo level = iota
+	levelWarning
+	levelError
+)
+
+func (l level) String() string {
+	switch l {
+	case levelWarning:
+		return "warning"
+	case levelError:
+		return "error"
+	}
+
+	return "info"
+}
+
+func (l level) render(s string) string {
+	switch l {
+	case levelWarning:
+		return warningStyle(s)
+	case levelError:
+		return errorStyle(s)
+	}
+
+	return messageStyle(s)
+}
+
+type notification struct {
+	level level
+	text  string
+	time  time.Time
+}
+
+func (n notification) String() string {
+	return fmt.Sprintf("%s %-7s %s", n.time.Format(time.TimeOnly), strings.ToUpper(n.level.String()), n.text)
+}
+
+// msgNotify shows a toast in the notification area and adds it to the history
+type msgNotify struct {
+	level level
+	text  string
+}
+
+// msgClearToast hides the toast with the given id once it expired
+type msgClearToast struct {
+	id int
+}
+
+func notifyCmd(level level, format string, a ...any) tea.Cmd {
+	return func() tea.Msg { return msgNotify{level: level, text: fmt.Sprintf(format, a...)} }
+}
+
+func (t tui) notify(msg msgNotify) (tea.Model, tea.Cmd) {
+	n := notification{level: msg.level, text: msg.text, time: time.Now()}
+
+	t.notifications = append(t.notifications, n)
+	if len(t.notifications) > maxNotifications {
+		t.notifications = t.notifications[len(t.notifications)-maxNotifications:]
+	}
+
+	t.toast = &n
+	t.toastId++
+	id := t.toastId
+
+	duration := infoToastDuration
+	if msg.level != levelInfo {
+		duration = errorToastDuration
+	}
+
+	return t, tea.Tick(duration, func(time.Time) tea.Msg { return msgClearToast{id: id} })
+}
+
+// RenderStatusBar renders the current toast or a summary of the history
+func (t *tui) RenderStatusBar() string {
+	if t.toast != nil {
+		return t.toast.level.render(fmt.Sprintf("%s %s", strings.ToUpper(t.toast.level.String()), t.toast.text))
+	}
+
+	if t.refreshing && !t.cachedAt.IsZero() {
+		return choiceStyle.Render(fmt.Sprintf("%s refreshing targets, showing targets cached %s ago", spinnerFrame, time.Since(t.cachedAt).Round(time.Second)))
+	}
+
+	if t.refreshing {
+		return choiceStyle.Render(fmt.Sprintf("%s refreshing targets", spinnerFrame))
+	}
+
+	if !t.cachedAt.IsZero() {
+		help := t.keyBindings.get(targetsBindings, "refresh").Help().Key
+		return levelWarning.render(fmt.Sprintf("showing targets cached %s ago, press %s to refresh", time.Since(t.cachedAt).Round(time.Second), help))
+	}
+
+	if len(t.notifications) == 0 {
+		return ""
+	}
+
+	errors := 0
+	for _, n := range t.notifications {
+		if n.level == levelError {
+			errors++
+		}
+	}
+
+	help := t.keyBindings.get(globalBindings, "notifications").Help().Key
+	return choiceStyle.Render(fmt.Sprintf("%d notification(s), %d error(s), press %s to show them", len(t.notifications), errors, help))
+}
+
+func (t tui) startNotifications() (tea.Model, tea.Cmd) {
+	t.historyViewport = viewport.New(0, 0)
+	t.resizeNotifications()
+
+	lines := make([]string, len(t.notifications))
+	for i, n := range t.notifications {
+		lines[i] = n.level.render(n.String())
+	}
+	t.historyViewport.SetContent(strings.Join(lines, "\n"))
+	t.historyViewport.GotoBottom()
+
+	t.SetState(notificationsView)
+	return t, nil
+}
+
+func (t *tui) resizeNotifications() {
+	// remove tabs, borders, title, footer and status bar from the window size
+	t.historyViewport.Width = max(0, t.width-2)
+	t.historyViewport.Height = max(0, t.height-7)
+}
+
+func (t tui) notificationsUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		t.width = msg.Width
+		t.height = msg.Height
+		t.resizeNotifications()
+
+		return t, t.UpdateTabs(t.tabsSize(msg))
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			t.state = t.previousState
+			return t, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	t.historyViewport, cmd = t.historyViewport.Update(msg)
+	return t, cmd
+}
+
+func (t tui) HandleNotificationsView() string {
+	content := t.historyViewport.View()
+	if len(t.notifications) == 0 {
+		content = choiceStyle.Render("No notifications")
+	}
+
+	footer := choiceStyle.Render(fmt.Sprintf("%3.f%%  ↑/↓ scroll  esc back", t.historyViewport.ScrollPercent()*100))
+
+	return t.RenderPane(t.previousState, highlight, "Notifications", content, footer)
+}
@@ -0,0 +1,274 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type connState int
+
+const (
+	stateIdle connState = iota
+	stateAuthorizing
+	stateStartingProxy
+	stateReady
+	stateFailed
+)
+
+func (s connState) String() string {
+	switch s {
+	case stateAuthorizing:
+		return "authorizing"
+	case stateStartingProxy:
+		return "starting proxy"
+	case stateReady:
+		return "ready"
+	case stateFailed:
+		return "failed"
+	}
+
+	return ""
+}
+
+// spinnerFrame is the current frame of the spinner shown next to targets
+// that are connecting, it is updated by the tui on every tick
+var spinnerFrame string
+
+// progress tracks an asynchronous connection to a target, it is shared with
+// the copy of the target used while connecting so it must be locked
+type progress struct {
+	mu        sync.Mutex
+	state     connState
+	cancel    context.CancelFunc
+	cancelled bool
+}
+
+// start marks the target as connecting and returns the context of the
+// connection, it returns false if the target is already connecting
+func (p *progress) start() (context.Context, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.busy() {
+		return nil, false
+	}
+
+	// the context is the parent of the session so it is only canceled if
+	// the user cancels the connection while it is still in flight
+	ctx, cancel := context.WithCancel(context.Background())
+	p.state = stateAuthorizing
+	p.cancel = cancel
+	p.cancelled = false
+
+	return ctx, true
+}
+
+func (p *progress) set(state connState) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.state = state
+}
+
+// finish records the result of the connection and reports if it was
+// cancelled by the user
+func (p *progress) finish(err error) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch {
+	case p.cancelled:
+		p.state = stateIdle
+	case err != nil:
+		p.state = stateFailed
+	default:
+		p.state = stateReady
+	}
+	p.cancel = nil
+
+	return p.cancelled
+}
+
+// abort cancels a connection that is still in flight
+func (p *progress) abort() bool {
+	if p == nil {
+		return false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.busy() {
+		return false
+	}
+
+	p.cancelled = true
+	p.cancel()
+	return true
+}
+
+func (p *progress) Busy() bool {
+	if p == nil {
+		return false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.busy()
+}
+
+func (p *progress) busy() bool {
+	return p.state == stateAuthorizing || p.state == stateStartingProxy
+}
+
+// String returns the spinner and state of a connecting or failed target
+func (p *progress) String() string {
+	if p == nil {
+		return ""
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch {
+	case p.busy():
+		return fmt.Sprintf("%s %s", spinnerFrame, p.state)
+	case p.state == stateFailed:
+		return fmt.Sprintf("(%s)", p.state)
+	}
+
+	return ""
+}
+
+// msgBusy starts the spinner
+type msgBusy struct{}
+
+// connectFunc is called from Update with the new session and its client
+// command once the session was added to the session manager
+type connectFunc func(session *SessionInfo, cmd *exec.Cmd) (tea.Cmd, error)
+
+// msgConnectResult is sent when an asynchronous connection finishes
+type msgConnectResult struct {
+	target  *Target
+	op      string
+	session *SessionInfo
+	cmd     *exec.Cmd
+	err     error
+	then    connectFunc
+}
+
+// connectAsync connects to the target without blocking the UI, the session
+// is only added to the session manager by Update when the connection succeeds
+func (t *Target) connectAsync(op string, port int, then connectFunc) tea.Cmd {
+	ctx, ok := t.progress.start()
+	if !ok {
+		return notifyCmd(levelWarning, "already connecting to %s", t.target.Name)
+	}
+
+	return tea.Batch(
+		func() tea.Msg { return msgBusy{} },
+		func() tea.Msg {
+			session, cmd, err := t.Connect(ctx, port)
+			return msgConnectResult{target: t, op: op, session: session, cmd: cmd, err: err, then: then}
+		},
+	)
+}
+
+func (t tui) connectResult(msg msgConnectResult) (tea.Model, tea.Cmd) {
+	target := msg.target
+
+	if target.progress.finish(msg.err) {
+		// the user gave up while the session was being established
+		t.removeConnected(target)
+		if msg.session != nil {
+			return t, terminateCmd(nil, msg.session)
+		}
+		return t, notifyCmd(levelInfo, "cancelled: %s", msg.op)
+	}
+
+	if msg.err != nil {
+		if errors.Is(msg.err, context.Canceled) {
+			return t, nil
+		}
+		return t, errorCmd(msg.op, msg.err)
+	}
+
+	t.sessions.Add(msg.session)
+
+	if msg.then == nil {
+		return t, nil
+	}
+
+	cmd, err := msg.then(msg.session, msg.cmd)
+	if err != nil {
+		return t, errorCmd(msg.op, err)
+	}
+	return t, cmd
+}
+
+// removeConnected removes a target that is no longer connecting from the
+// connected tab
+func (t *tui) removeConnected(target *Target) {
+	connected := t.tabs[connectedView]
+	if i := slices.Index(connected.Items(), list.Item(target)); i >= 0 {
+		connected.RemoveItem(i)
+	}
+}
+
+// terminateCmd terminates sessions without blocking the UI, done is sent
+// when every session was terminated
+func terminateCmd(done tea.Msg, sessions ...*SessionInfo) tea.Cmd {
+	return func() tea.Msg {
+		failed := []string{}
+		var err error
+		for _, session := range sessions {
+			if terminateErr := session.Terminate(); terminateErr != nil {
+				failed = append(failed, session.SessionId)
+				err = terminateErr
+			}
+		}
+
+		if len(failed) > 0 {
+			return msgNotify{level: levelWarning, text: fmt.Sprintf("could not cancel session %s: %s", strings.Join(failed, ", "), describeError(err))}
+		}
+		return done
+	}
+}
+
+// busy reports if any target is connecting or the targets are being refreshed
+func (t *tui) busy() bool {
+	if t.refreshing {
+		return true
+	}
+
+	for _, tab := range t.tabs {
+		for _, item := range tab.Items() {
+			if target := itemTarget(item); target != nil && target.progress.Busy() {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (t *tui) startSpinner() tea.Cmd {
+	if t.spinning {
+		return nil
+	}
+
+	t.spinning = true
+	spinnerFrame = t.spinner.View()
+	return t.spinner.Tick
+}
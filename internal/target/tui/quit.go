@@ -15,18 +15,8 @@ func (t tui) quit(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (t tui) gracefullyQuit(msg tea.Msg) (tea.Model, tea.Cmd) {
-	sessions := 0
-
 	// if there are no active sessions, we can quit immediately
-	for _, item := range t.tabs[connectedView].Items() {
-		if target, ok := item.(*Target); ok {
-			if target.IsConnected() {
-				sessions++
-			}
-		}
-	}
-
-	if sessions == 0 {
+	if t.sessions.Active() == 0 {
 		return t.quit(msg)
 	}
 
@@ -36,6 +26,8 @@ func (t tui) gracefullyQuit(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (t tui) quittingUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		return t.resize(msg)
 	case tea.KeyMsg:
 		// For simplicity's sake, we'll treat any key besides "y" as "no"
 		if msg.String() == "y" {
@@ -51,16 +43,7 @@ func (t tui) quittingUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (t tui) HandleQuittingView() string {
 	// if we get here, it means we have active sessions
-	sessions := 0
-
-	// check if there are any active sessions
-	for _, item := range t.tabs[connectedView].Items() {
-		if target, ok := item.(*Target); ok {
-			if target.IsConnected() {
-				sessions++
-			}
-		}
-	}
+	sessions := t.sessions.Active()
 
 	text := alertViewStyle.Render(
 		lipgloss.JoinHorizontal(
@@ -74,8 +57,8 @@ func (t tui) HandleQuittingView() string {
 	paddingWidth := (t.width - lipgloss.Width(text)) / 2
 
 	return lipgloss.NewStyle().Padding(
-		paddingHeight-1,
-		paddingWidth,
+		max(0, paddingHeight-1),
+		max(0, paddingWidth),
 		0,
 	).Render(text)
 }
@@ -2,17 +2,29 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
 	"net/netip"
+	"os"
 	"os/exec"
+	"path"
 	"strconv"
+	"sync"
+	"syscall"
 	"time"
 
+	"github.com/AndreZiviani/boundary-fuzzy/internal/audit"
+	"github.com/AndreZiviani/boundary-fuzzy/internal/config"
+	"github.com/AndreZiviani/boundary-fuzzy/internal/recording"
 	"github.com/AndreZiviani/boundary-fuzzy/internal/run"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/boundary/api"
 	apiproxy "github.com/hashicorp/boundary/api/proxy"
 	"github.com/hashicorp/boundary/api/sessions"
 	"github.com/hashicorp/boundary/api/targets"
+	"github.com/pkg/browser"
 	"go.uber.org/atomic"
 )
 
@@ -20,40 +32,79 @@ type Target struct {
 	targetClient   *targets.Client
 	sessionsClient *sessions.Client
 	title          string
-	rtitle         string
 	description    string
-	rdescription   string
 	target         *targets.Target
-	session        *SessionInfo
-	task           *run.Task
+	sessions       *sessionManager
+	settings       config.Settings
+	policy         config.Policy
+	userId         string
+	progress       *progress
 }
 
 func (t Target) Title(tab sessionState) (string, string) {
-	switch tab {
-	case connectedView:
-		return t.title, t.rtitle
+	if status := t.progress.String(); status != "" {
+		return t.title, status
 	}
 
 	return t.title, ""
 }
 func (t Target) Description(tab sessionState) (string, string) {
-	switch tab {
-	case connectedView:
-		return t.description, t.rdescription
-	}
-
 	return t.description, ""
 }
 
 func (t Target) FilterValue() string { return t.title }
 
+// Sessions returns the sessions of this target, oldest first
+func (t *Target) Sessions() []*SessionInfo {
+	return t.sessions.ForTarget(t.target.Id)
+}
+
+// sessions are listed in the connected tab, one row per session
+
+func (s *SessionInfo) Title(tab sessionState) (string, string) {
+	// a session that ended is replaced when the target is reconnected
+	if status := s.target.progress.String(); status != "" && !s.IsActive() {
+		return s.target.title, status
+	}
+
+	if s.limited() {
+		return s.target.title, fmt.Sprintf("(%d, %d/%d connections left)", s.Port, max(0, s.ConnectionsLeft()), s.ConnectionLimit)
+	}
+
+	return s.target.title, fmt.Sprintf("(%d)", s.Port)
+}
+
+func (s *SessionInfo) Description(tab sessionState) (string, string) {
+	return s.target.description, fmt.Sprintf("(%s)", s.Expiration.Local().Format(time.RFC3339))
+}
+
+func (s *SessionInfo) FilterValue() string { return s.target.title }
+
+func (s *SessionInfo) IsConnected() bool { return s.IsActive() }
+
 type SessionInfo struct {
 	ctx                context.Context
 	cancel             context.CancelFunc
 	clientProxyCloseCh chan struct{}
-	active             bool
 
-	sessionClient *sessions.Client
+	// target is the target this session was authorized for
+	target *Target
+
+	// changed is signaled when the number of connections left changes
+	changed chan struct{}
+
+	// mu guards the fields below, the session is shared with the goroutines
+	// watching the proxy and the background process
+	mu              sync.Mutex
+	status          sessionStatus
+	task            *run.Task
+	cleanups        []func()
+	connectionsLeft int32
+	sessionClient   *sessions.Client
+
+	// audit is set when connections to this session must be logged
+	audit       bool
+	auditRecord audit.Record
 
 	authorizationToken string
 	Address            string
@@ -62,68 +113,100 @@ type SessionInfo struct {
 	ConnectionLimit    int32
 	SessionId          string
 	Credentials        []*targets.SessionCredential
+	// Endpoint is the host the session was authorized for, e.g.
+	// tcp://10.0.0.1:6443
+	Endpoint string
 }
 
-func (t *Target) Connect() (*exec.Cmd, error) {
-	err := t.newSessionProxy(context.Background())
+// Connect starts a session and returns it with the client command for the
+// target, the proxy listens on port or on a random port if it is 0. It blocks
+// until the proxy is listening so it must not be called from Update
+func (t *Target) Connect(ctx context.Context, port int) (*SessionInfo, *exec.Cmd, error) {
+	session, err := t.newSessionProxy(ctx, port)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	cmd, err := session.command()
+	if err != nil {
+		session.Terminate()
+		return nil, nil, err
+	}
+
+	return session, cmd, nil
+}
+
+// command returns the client for the target of this session, it is nil for
+// targets without a known client
+func (s *SessionInfo) command() (*exec.Cmd, error) {
+	t := s.target
+	if launcher, ok := t.launcher(); ok {
+		return s.newLauncherCommand(launcher), nil
 	}
 
-	var cmd *exec.Cmd
-	if port, ok := t.target.Attributes["default_port"]; ok {
-		switch int(port.(float64)) {
-		case 5432:
-			cmd = NewPSQLCommand("127.0.0.1", t.session.Port, t.session.Credentials)
+	switch t.defaultPort() {
+	case 5432:
+		return NewPSQLCommand(s.Address, s.Port, s.Credentials, t.settings, t.policy.ReadOnly), nil
 
-		case 3306:
-			cmd = NewMySQLCommand("127.0.0.1", t.session.Port, t.session.Credentials)
+	case 3306:
+		return NewMySQLCommand(s.Address, s.Port, s.Credentials, t.settings, t.policy.ReadOnly), nil
 
-		case 6379:
-			cmd = NewRedisCommand("127.0.0.1", t.session.Port, t.session.Credentials)
+	case 6379:
+		return NewRedisCommand(s.Address, s.Port, s.Credentials, t.settings, t.policy.ReadOnly), nil
 
-		case 9440:
-			cmd = NewClickHouseCommand("127.0.0.1", t.session.Port, t.session.Credentials)
+	case 9440:
+		return NewClickHouseCommand(s.Address, s.Port, s.Credentials, t.settings, t.policy.ReadOnly), nil
 
-		default:
-			// do nothing, just connect to target
-		}
+	case kubernetesPort:
+		return s.newKubernetesCommand()
 	}
 
-	return cmd, nil
+	// do nothing, just connect to target
+	return nil, nil
 }
 
-func (t *Target) newSessionProxy(mainCtx context.Context) error {
+func (t *Target) newSessionProxy(mainCtx context.Context, port int) (*SessionInfo, error) {
+	addr, err := netip.ParseAddr(t.settings.ProxyListenAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	t.progress.set(stateAuthorizing)
 	session, err := t.targetClient.AuthorizeSession(mainCtx, t.target.Id)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	auth, err := session.GetSessionAuthorization()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	ctx, cancel := context.WithCancel(mainCtx)
 	si := &SessionInfo{
 		ctx:    ctx,
 		cancel: cancel,
-		active: true,
 
+		target:             t,
 		sessionClient:      t.sessionsClient,
+		connectionsLeft:    auth.ConnectionLimit,
+		changed:            make(chan struct{}, 1),
 		authorizationToken: auth.AuthorizationToken,
 		Expiration:         auth.Expiration,
 		ConnectionLimit:    auth.ConnectionLimit,
 		SessionId:          auth.SessionId,
 		Credentials:        auth.Credentials,
+		Endpoint:           auth.Endpoint,
 	}
 
-	addr, err := netip.ParseAddr("127.0.0.1")
-	if err != nil {
+	// the session was authorized, make sure it is canceled if the proxy fails
+	fail := func(err error) error {
+		si.Terminate()
 		return err
 	}
 
-	listenAddr := netip.AddrPortFrom(addr, 0)
+	t.progress.set(stateStartingProxy)
+	listenAddr := netip.AddrPortFrom(addr, uint16(port))
 
 	connsLeftCh := make(chan int32)
 	apiProxyOpts := []apiproxy.Option{
@@ -131,13 +214,26 @@ func (t *Target) newSessionProxy(mainCtx context.Context) error {
 		apiproxy.WithListenAddrPort(listenAddr),
 	}
 
+	// the port may still be held by the session this one replaces
+	var listener net.Listener
+	if port != 0 {
+		listener, err = listen(ctx, listenAddr, t.settings.ProxyListenTimeout.Duration)
+		if err != nil {
+			return nil, fail(fmt.Errorf("could not start proxy listener: %w", err))
+		}
+		apiProxyOpts = append(apiProxyOpts, apiproxy.WithListener(listener))
+	}
+
 	clientProxy, err := apiproxy.New(
 		ctx,
 		auth.AuthorizationToken,
 		apiProxyOpts...,
 	)
 	if err != nil {
-		return err
+		if listener != nil {
+			listener.Close()
+		}
+		return nil, fail(err)
 	}
 
 	clientProxyCloseCh := make(chan struct{})
@@ -147,7 +243,6 @@ func (t *Target) newSessionProxy(mainCtx context.Context) error {
 	go func() {
 		defer close(clientProxyCloseCh)
 		proxyError.Store(clientProxy.Start())
-		si.active = false
 	}()
 	go func() {
 		defer close(connCountCloseCh)
@@ -158,6 +253,7 @@ func (t *Target) newSessionProxy(mainCtx context.Context) error {
 				// done it manually
 				return
 			case connsLeft := <-connsLeftCh:
+				si.setConnectionsLeft(connsLeft)
 				if connsLeft == 0 {
 					return
 				}
@@ -165,35 +261,80 @@ func (t *Target) newSessionProxy(mainCtx context.Context) error {
 		}
 	}()
 
-	listenerCtx, listenerCancel := context.WithTimeout(ctx, 5*time.Second)
+	listenerCtx, listenerCancel := context.WithTimeout(ctx, t.settings.ProxyListenTimeout.Duration)
 	defer listenerCancel()
 	proxyAddr := clientProxy.ListenerAddress(listenerCtx)
 	if listenerCtx.Err() != nil {
-		cancel()
 		proxyErr := proxyError.Load()
 		if proxyErr != nil {
-			return fmt.Errorf("could not start proxy: %w", proxyErr)
+			return nil, fail(fmt.Errorf("could not start proxy: %w", proxyErr))
 		}
-		return fmt.Errorf("could not start proxy listener: %w", listenerCtx.Err())
+		return nil, fail(fmt.Errorf("could not start proxy listener: %w", listenerCtx.Err()))
 	}
 	clientProxyHost, clientProxyPort, err := SplitHostPort(proxyAddr)
 	if err != nil {
-		return err
+		return nil, fail(err)
 	}
 
 	si.Address = clientProxyHost
 	si.Port, _ = strconv.Atoi(clientProxyPort)
 	si.clientProxyCloseCh = clientProxyCloseCh
 
-	t.session = si
+	start := time.Now()
+	si.audit = t.settings.AuditLog || t.policy.Audit
+	si.auditRecord = audit.Record{
+		UserId:     t.userId,
+		TargetId:   t.target.Id,
+		TargetName: t.target.Name,
+		Scope:      t.target.Scope.Name,
+		SessionId:  si.SessionId,
+		Port:       si.Port,
+		Start:      &start,
+	}
+	si.log(audit.EventConnect, nil)
 
-	t.rtitle = fmt.Sprintf("(%s)", clientProxyPort)
-	t.rdescription = fmt.Sprintf("(%s)", si.Expiration.Local().Format(time.RFC3339))
+	return si, nil
+}
 
-	return nil
+// listen binds addr, retrying while it is in use until timeout, the listener
+// of a spent session is closed asynchronously by its proxy
+func listen(ctx context.Context, addr netip.AddrPort, timeout time.Duration) (net.Listener, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	backoff := 10 * time.Millisecond
+	for {
+		listener, err := net.ListenTCP("tcp", net.TCPAddrFromAddrPort(addr))
+		if err == nil {
+			return listener, nil
+		}
+		if !errors.Is(err, syscall.EADDRINUSE) {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, 500*time.Millisecond)
+	}
 }
 
 func (t Target) Info() string {
+	msg := t.details()
+	for _, session := range t.Sessions() {
+		msg = fmt.Sprintf("%s\n%s", msg, session.details())
+	}
+
+	return msg
+}
+
+func (s *SessionInfo) Info() string {
+	return fmt.Sprintf("%s\n%s", s.target.details(), s.details())
+}
+
+func (t Target) details() string {
 	msg := fmt.Sprintf(
 		"Scope: %s\n"+
 			"Scope Description: %s\n"+
@@ -202,86 +343,384 @@ func (t Target) Info() string {
 		t.target.Scope.Name, t.target.Scope.Description, t.target.Name, t.target.Description,
 	)
 
-	if t.session != nil {
-		status := "connected"
-		if t.session.ctx.Err() != nil {
-			status = t.session.ctx.Err().Error()
-		} else if !t.session.active {
-			status = "disconnected"
-		}
+	if t.policy.Banner != "" {
+		msg = fmt.Sprintf("%s\n%s\n", msg, bannerStyle(t.policy.Banner))
+	}
 
+	return msg
+}
+
+func (s *SessionInfo) details() string {
+	msg := fmt.Sprintf(
+		"Port: %d\n"+
+			"Expiration: %s\n"+
+			"Session Id: %s\n"+
+			"Status: %s\n",
+		s.Port, s.Expiration, s.SessionId, s.Status(),
+	)
+
+	if s.limited() {
+		msg = fmt.Sprintf("%sConnections Left: %d/%d\n", msg, max(0, s.ConnectionsLeft()), s.ConnectionLimit)
+	}
+
+	if task := s.Task(); task != nil {
+		msg = fmt.Sprintf("%sBackground process: %s\n", msg, task.Status())
+	}
+
+	if len(s.Credentials) > 0 {
 		msg = fmt.Sprintf(
 			"%s\n"+
-				"Port: %d\n"+
-				"Expiration: %s\n"+
-				"Session Id: %s\n"+
-				"Status: %s\n",
-			msg, t.session.Port, t.session.Expiration, t.session.SessionId, status,
+				"Dynamic Credentials:\n"+
+				"  Username: %s\n"+
+				"  Password: %s\n",
+			msg, s.Credentials[0].Secret.Decoded["username"], s.Credentials[0].Secret.Decoded["password"],
 		)
+	}
 
-		if len(t.session.Credentials) > 0 {
-			msg = fmt.Sprintf(
-				"%s\n"+
-					"Dynamic Credentials:\n"+
-					"  Username: %s\n"+
-					"  Password: %s\n",
-				msg, t.session.Credentials[0].Secret.Decoded["username"], t.session.Credentials[0].Secret.Decoded["password"],
-			)
+	return msg
+}
+
+// log writes an audit record for this session if auditing is enabled, update
+// can be used to add event specific fields to the record
+func (s *SessionInfo) log(event string, update func(r *audit.Record)) {
+	if !s.audit {
+		return
+	}
+
+	record := s.auditRecord
+	record.Event = event
+	if update != nil {
+		update(&record)
+	}
+
+	// the audit log must never stop the user from working
+	_ = audit.Log(record)
+}
+
+// logCommand writes an audit record for a command run against this session,
+// the credentials are redacted from its arguments
+func (s *SessionInfo) logCommand(cmd *exec.Cmd, start time.Time, recordingFile string, err error) {
+	secrets := []string{}
+	for _, credential := range s.Credentials {
+		if password, ok := credential.Secret.Decoded["password"].(string); ok {
+			secrets = append(secrets, password)
 		}
 	}
 
-	return msg
+	s.log(audit.EventShell, func(r *audit.Record) {
+		end := time.Now()
+		r.Start = &start
+		r.End = &end
+		r.Command = audit.Redact(cmd.Args, secrets...)
+		r.Recording = recordingFile
+		if cmd.ProcessState != nil {
+			exitStatus := cmd.ProcessState.ExitCode()
+			r.ExitStatus = &exitStatus
+		}
+		if err != nil {
+			r.Error = err.Error()
+		}
+	})
 }
 
-func (s *SessionInfo) Terminate(task *run.Task) {
-	ctx := context.Background()
+// onTerminate registers a function to be called when the session ends
+func (s *SessionInfo) onTerminate(cleanup func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cleanups = append(s.cleanups, cleanup)
+}
+
+// client returns the client used to manage the session on the controller
+func (s *SessionInfo) client() *sessions.Client {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sessionClient
+}
+
+// setClient replaces the session client after the user logged in again
+func (s *SessionInfo) setClient(sessionClient *sessions.Client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessionClient = sessionClient
+}
+
+// ConnectionsLeft returns how many connections the session still allows, it
+// is negative if there is no limit
+func (s *SessionInfo) ConnectionsLeft() int32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connectionsLeft
+}
+
+func (s *SessionInfo) setConnectionsLeft(left int32) {
+	s.mu.Lock()
+	s.connectionsLeft = left
+	s.mu.Unlock()
+
+	select {
+	case s.changed <- struct{}{}:
+	default:
+		// the watcher did not pick up the previous change yet
+	}
+}
+
+// limited reports if the session only allows a number of connections
+func (s *SessionInfo) limited() bool {
+	return s.ConnectionLimit > 0
+}
+
+func (s *SessionInfo) Status() sessionStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+// IsActive reports if the session can still be used
+func (s *SessionInfo) IsActive() bool {
+	return s.Status() == sessionActive
+}
+
+// Task returns the background process of this session, if any
+func (s *SessionInfo) Task() *run.Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.task
+}
+
+// setTask attaches a background process to the session, it fails if the
+// session already ended because nothing would stop the process
+func (s *SessionInfo) setTask(task *run.Task) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.status != sessionActive {
+		return false
+	}
+
+	s.task = task
+	return true
+}
+
+// end stops the proxy and the background process and runs the cleanups, it
+// only records the first status and reports if this call ended the session
+func (s *SessionInfo) end(status sessionStatus) bool {
+	s.mu.Lock()
+	if s.status != sessionActive {
+		s.mu.Unlock()
+		return false
+	}
+	s.status = status
+	task, cleanups := s.task, s.cleanups
+	s.cleanups = nil
+	s.mu.Unlock()
+
 	s.cancel()
 
-	sessionInfo, err := s.sessionClient.Read(ctx, s.SessionId)
-	if err != nil {
-		return
+	if task != nil {
+		task.Stop(run.DefaultGracePeriod)
+	}
+
+	for _, cleanup := range cleanups {
+		cleanup()
 	}
 
-	s.sessionClient.Cancel(ctx, s.SessionId, sessionInfo.Item.Version)
+	s.log(audit.EventTerminate, func(r *audit.Record) {
+		end := time.Now()
+		r.End = &end
+	})
+
+	return true
 }
 
-func (t *Target) Shell(callbackFn tea.ExecCallback) (tea.Cmd, error) {
-	cmd, err := t.Connect()
-	if err != nil {
-		return nil, err
+// Terminate ends the session and cancels it on the controller, the returned
+// error means the session may still be active
+func (s *SessionInfo) Terminate() error {
+	return s.terminate(sessionTerminated)
+}
+
+func (s *SessionInfo) terminate(status sessionStatus) error {
+	ctx := context.Background()
+	s.end(status)
+
+	sessionClient := s.client()
+	sessionInfo, err := sessionClient.Read(ctx, s.SessionId)
+	if apiErr := api.AsServerError(err); apiErr != nil && apiErr.Response() != nil && apiErr.Response().StatusCode() == http.StatusNotFound {
+		// the session expired and was already removed
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if sessionInfo.Item.Status == "terminated" {
+		return nil
+	}
+
+	_, err = sessionClient.Cancel(ctx, s.SessionId, sessionInfo.Item.Version)
+	return err
+}
+
+// Shell connects to the target and opens its client
+func (t *Target) Shell() tea.Cmd {
+	op := fmt.Sprintf("open shell on %s", t.target.Name)
+	return t.connectAsync(op, 0, func(session *SessionInfo, cmd *exec.Cmd) (tea.Cmd, error) {
+		if t.isBackground() {
+			if err := session.startBackground(cmd); err != nil {
+				return nil, err
+			}
+
+			return tea.Sequence(
+				func() tea.Msg { return msgConnect{target: t, session: session} },
+				notifyCmd(levelInfo, "started %s in the background", t.target.Name),
+			), nil
+		}
+
+		if cmd == nil {
+			// we are trying to connect to a target that we could not identify its type or does not have a client
+			// just connect to it and open it in the browser if it looks like a web endpoint
+			return session.openWithoutClient(), nil
+		}
+
+		return session.runInteractive(cmd, execCallback(op))
+	})
+}
+
+// SubShell connects to the target and opens the user shell with the session
+// details in its environment so any tool can be used against the target
+func (t *Target) SubShell() tea.Cmd {
+	op := fmt.Sprintf("open sub-shell on %s", t.target.Name)
+	return t.connectAsync(op, 0, func(session *SessionInfo, _ *exec.Cmd) (tea.Cmd, error) {
+		cmd := exec.Command(userShell())
+		cmd.Env = append(os.Environ(), session.env()...)
+
+		return session.runInteractive(cmd, execCallback(op))
+	})
+}
+
+// Reconnect terminates the session and starts a new one in its place, on the
+// same local port
+func (s *SessionInfo) Reconnect() tea.Cmd {
+	t := s.target
+	if t.progress.Busy() {
+		return notifyCmd(levelWarning, "already connecting to %s", t.target.Name)
 	}
 
-	if cmd == nil {
-		// we are trying to connect to a target that we could not identify its type or does not have a client (e.g. HTTP)
-		// just connect to it without opening a shell
-		//TODO: show error message
-		return nil, nil
+	t.sessions.Remove(s)
+	return tea.Sequence(terminateCmd(nil, s), t.reconnect(s, s.Port))
+}
+
+// Reconnect retries a connection that failed
+func (t *Target) Reconnect() tea.Cmd {
+	if t.progress.Busy() {
+		return notifyCmd(levelWarning, "already connecting to %s", t.target.Name)
 	}
 
-	return tea.ExecProcess(
-		cmd,
-		func(err error) tea.Msg {
-			t.session.Terminate(t.task)
+	return t.reconnect(nil, 0)
+}
+
+func (t *Target) reconnect(replaces *SessionInfo, port int) tea.Cmd {
+	return t.connectAsync(fmt.Sprintf("reconnect to %s", t.target.Name), port, func(session *SessionInfo, cmd *exec.Cmd) (tea.Cmd, error) {
+		if t.isBackground() {
+			if err := session.startBackground(cmd); err != nil {
+				return nil, err
+			}
+		}
+
+		return tea.Sequence(
+			func() tea.Msg { return msgConnect{target: t, session: session, replaces: replaces} },
+			notifyCmd(levelInfo, "reconnected to %s on port %d", t.target.Name, session.Port),
+		), nil
+	})
+}
+
+func execCallback(op string) tea.ExecCallback {
+	return func(err error) tea.Msg {
+		return msgError{err: newOpError(op, err)}
+	}
+}
+
+// runInteractive hands the terminal to cmd, the session is terminated when it exits
+func (s *SessionInfo) runInteractive(cmd *exec.Cmd, callbackFn tea.ExecCallback) (tea.Cmd, error) {
+	t := s.target
+	start := time.Now()
+
+	recordingFile := ""
+	callback := func(err error) tea.Msg {
+		s.logCommand(cmd, start, recordingFile, err)
+		t.sessions.Remove(s)
+		err = errors.Join(err, s.Terminate())
+		if err != nil {
+			return callbackFn(err)
+		}
+		return nil
+	}
+
+	if t.settings.RecordSessions || t.policy.Record {
+		recorder, err := s.newRecorder(cmd)
+		if err != nil {
+			t.sessions.Remove(s)
+			s.Terminate()
+			return nil, err
+		}
+		recordingFile = recorder.File()
+
+		return tea.Exec(recorder, callback), nil
+	}
+
+	return tea.ExecProcess(cmd, callback), nil
+}
+
+// openWithoutClient opens HTTP targets in the browser and tells the user how
+// to reach the other ones
+func (s *SessionInfo) openWithoutClient() tea.Cmd {
+	t := s.target
+	connected := msgConnected{session: s}
+
+	if scheme, ok := httpScheme(t.target); ok {
+		connected.url = s.URL(scheme)
+		if t.settings.HTTPRewriteHost && t.target.Address != "" {
+			// keep the direct URL if the proxy can not be started
+			rewritten, err := s.rewriteHost(scheme, t.target.Address)
 			if err != nil {
-				return callbackFn(err)
+				connected.err = fmt.Errorf("could not rewrite the Host header: %w", err)
+			} else {
+				connected.url = rewritten
 			}
-			return nil
-		},
-	), nil
+		}
+
+		if connected.err == nil {
+			connected.err = browser.OpenURL(connected.url)
+		}
+	}
+
+	return tea.Sequence(
+		func() tea.Msg { return msgConnect{target: t, session: s} },
+		func() tea.Msg { return connected },
+	)
 }
 
-func (t *Target) IsConnected() bool {
-	if t.session == nil {
-		return false
+// newRecorder wraps cmd so the session is recorded in the recordings folder
+func (s *SessionInfo) newRecorder(cmd *exec.Cmd) (*recording.Recorder, error) {
+	t := s.target
+	if err := recording.Prune(t.settings.RecordingRetention.Duration); err != nil {
+		return nil, err
 	}
 
-	if t.session.ctx.Err() != nil {
-		return false
+	folder, err := recording.Folder()
+	if err != nil {
+		return nil, err
 	}
 
-	if !t.session.active {
-		return false
+	file := path.Join(folder, recording.NewFileName(t.target.Name, s.SessionId))
+	return recording.NewRecorder(cmd, file, t.title), nil
+}
+
+// IsConnected reports if the target has at least one active session
+func (t *Target) IsConnected() bool {
+	for _, session := range t.Sessions() {
+		if session.IsActive() {
+			return true
+		}
 	}
 
-	return true
+	return false
 }
@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/AndreZiviani/boundary-fuzzy/internal/config"
+	"github.com/hashicorp/boundary/api/targets"
+)
+
+func TestListenWaitsForThePort(t *testing.T) {
+	held, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := netip.MustParseAddrPort(held.Addr().String())
+
+	// the proxy of the replaced session closes its listener a bit later
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		held.Close()
+	}()
+
+	listener, err := listen(context.Background(), addr, 5*time.Second)
+	if err != nil {
+		t.Fatalf("listen() = %v, want the port once it is released", err)
+	}
+	listener.Close()
+}
+
+func TestListenTimeout(t *testing.T) {
+	held, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer held.Close()
+
+	if _, err := listen(context.Background(), netip.MustParseAddrPort(held.Addr().String()), 100*time.Millisecond); err == nil {
+		t.Fatal("listen() succeeded on a port that is still in use")
+	}
+}
+
+func TestOpenWithoutClientKeepsURLWhenRewriteFails(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	session := &SessionInfo{
+		ctx: ctx,
+		target: &Target{
+			target: &targets.Target{
+				Name:       "grafana",
+				Address:    "grafana.example.com",
+				Attributes: map[string]any{"scheme": "https"},
+			},
+			settings: config.Settings{HTTPRewriteHost: true},
+			progress: &progress{},
+		},
+		// the Host-rewrite proxy can not listen on this address
+		Address: "256.0.0.1",
+		Port:    8443,
+	}
+
+	var connected *msgConnected
+	for _, msg := range runCmd(session.openWithoutClient()) {
+		if msg, ok := msg.(msgConnected); ok {
+			connected = &msg
+		}
+	}
+
+	if connected == nil {
+		t.Fatal("no msgConnected was sent")
+	}
+	if connected.url != "https://256.0.0.1:8443" {
+		t.Fatalf("url is %q, want the direct URL of the session", connected.url)
+	}
+	if connected.err == nil {
+		t.Fatal("the rewrite error was not reported")
+	}
+}
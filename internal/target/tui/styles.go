@@ -1,6 +1,9 @@
 package tui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+)
 
 const (
 	bullet   = "•"
@@ -15,10 +18,22 @@ var (
 	errorStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.AdaptiveColor{Light: "#cc0000", Dark: "#cc0000"}).
 			Render
+	warningStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.AdaptiveColor{Light: "#af8700", Dark: "#d7af00"}).
+			Render
+	bannerStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.AdaptiveColor{Light: "#cc0000", Dark: "#cc0000"}).
+			Render
+
+	helpTitleStyle = lipgloss.NewStyle().Bold(true).Foreground(highlight)
+	helpKeyStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
 
 	windowStyle = lipgloss.NewStyle().BorderForeground(highlight).Align(lipgloss.Left).Border(lipgloss.NormalBorder()).UnsetBorderTop()
 
-	highlight = lipgloss.AdaptiveColor{Light: "#874BFD", Dark: "#7D56F4"}
+	highlight lipgloss.TerminalColor = lipgloss.AdaptiveColor{Light: "#874BFD", Dark: "#7D56F4"}
+
+	itemStyles = list.NewDefaultItemStyles()
 
 	activeTabBorder = lipgloss.Border{
 		Top:         "─",
@@ -1,6 +1,9 @@
 package tui
 
 import (
+	"fmt"
+	"slices"
+
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
@@ -10,44 +13,57 @@ const (
 	connectedTabName = "Connected"
 )
 
+// the connected tab lists one row per session, targets are only listed while
+// they are connecting or when the connection failed
+
 func ConnectedUpdate(t targetDelegate, msg tea.Msg, m *list.Model) tea.Cmd {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, t.keyMap.binding["reconnect"]):
-			if i, ok := m.SelectedItem().(*Target); ok {
-				i.session.Terminate(i.task)
-				i.session = nil
-				_, err := i.Connect()
-				if err != nil {
-					return tea.Sequence(func() tea.Msg { return msgError{err: err} })
-				}
-				return nil
+			switch i := m.SelectedItem().(type) {
+			case *SessionInfo:
+				return i.Reconnect()
+			case *Target:
+				return i.Reconnect()
 			}
 
 		case key.Matches(msg, t.keyMap.binding["disconnect"]):
-			if i, ok := m.SelectedItem().(*Target); ok {
-				i.session.Terminate(i.task)
-				i.session = nil
+			switch i := m.SelectedItem().(type) {
+			case *SessionInfo:
+				m.RemoveItem(m.Index())
+				m.CursorUp()
+
+				i.target.sessions.Remove(i)
+				return terminateCmd(msgNotify{level: levelInfo, text: fmt.Sprintf("disconnected from %s on port %d", i.target.target.Name, i.Port)}, i)
+
+			case *Target:
 				m.RemoveItem(m.Index())
 				m.CursorUp()
 
+				// the connection result reports the cancellation
+				i.progress.abort()
 				return nil
 			}
 
 		case key.Matches(msg, t.keyMap.binding["info"]):
-			if i, ok := m.SelectedItem().(*Target); ok {
-				return tea.Sequence(func() tea.Msg { return msgInfo{target: i} })
+			if i, ok := m.SelectedItem().(interface{ Info() string }); ok {
+				return tea.Sequence(func() tea.Msg { return msgInfo{text: i.Info()} })
+			}
+
+		case key.Matches(msg, t.keyMap.binding["logs"]):
+			if i, ok := m.SelectedItem().(*SessionInfo); ok && i.Task() != nil {
+				return tea.Sequence(func() tea.Msg { return msgLogs{session: i} })
 			}
 
 		case key.Matches(msg, t.keyMap.binding["favorite"]):
-			if i, ok := m.SelectedItem().(*Target); ok {
-				return tea.Sequence(func() tea.Msg { return msgFavorite{target: i} })
+			if target := itemTarget(m.SelectedItem()); target != nil {
+				return tea.Sequence(func() tea.Msg { return msgFavorite{target: target} })
 			}
 		}
 
 	case msgConnect:
-		return m.InsertItem(len(m.Items()), msg.target)
+		return insertConnected(m, msg)
 
 	case tea.WindowSizeMsg:
 		m.SetSize(msg.Width, msg.Height)
@@ -56,3 +72,54 @@ func ConnectedUpdate(t targetDelegate, msg tea.Msg, m *list.Model) tea.Cmd {
 
 	return nil
 }
+
+// insertConnected adds a connecting target or a new session to the connected
+// tab, the session takes the place of the target or of the session it replaces
+func insertConnected(m *list.Model, msg msgConnect) tea.Cmd {
+	items := m.Items()
+
+	if msg.session == nil {
+		if slices.Contains(items, list.Item(msg.target)) {
+			return nil
+		}
+		return m.InsertItem(len(items), msg.target)
+	}
+
+	if slices.Contains(items, list.Item(msg.session)) {
+		return nil
+	}
+
+	index := slices.IndexFunc(items, func(item list.Item) bool {
+		if msg.replaces != nil && item == list.Item(msg.replaces) {
+			return true
+		}
+		target, ok := item.(*Target)
+		return ok && target.target.Id == msg.session.target.target.Id
+	})
+	if index < 0 {
+		return m.InsertItem(len(items), msg.session)
+	}
+
+	cmd := m.SetItem(index, msg.session)
+
+	// the target may also have been waiting in the list
+	if msg.replaces != nil {
+		if placeholder := slices.Index(m.Items(), list.Item(msg.target)); placeholder >= 0 {
+			m.RemoveItem(placeholder)
+		}
+	}
+
+	return cmd
+}
+
+// itemTarget returns the target of a target or session row
+func itemTarget(item list.Item) *Target {
+	switch i := item.(type) {
+	case *Target:
+		return i
+	case *SessionInfo:
+		return i.target
+	}
+
+	return nil
+}
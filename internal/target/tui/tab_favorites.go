@@ -19,31 +19,31 @@ func FavoritesUpdate(t targetDelegate, msg tea.Msg, m *list.Model) tea.Cmd {
 			if _, ok := m.SelectedItem().(*Target); ok {
 				m.RemoveItem(m.Index())
 				m.CursorUp()
-				saveFavoriteList(*m)
+				if err := saveFavoriteList(*m); err != nil {
+					return errorCmd("save favorites", err)
+				}
 				return nil
 			}
 
 		case key.Matches(msg, t.keyMap.binding["shell"]):
 			if i, ok := m.SelectedItem().(*Target); ok {
-				cmd, err := i.Shell(func(err error) tea.Msg {
-					return tea.Sequence(func() tea.Msg { return msgError{err: err} })
-				})
+				return guard(i, shellAction(i))
+			}
 
-				if err != nil {
-					return tea.Sequence(cmd, func() tea.Msg { return msgError{err: err} })
-				}
+		case key.Matches(msg, t.keyMap.binding["subshell"]):
+			if i, ok := m.SelectedItem().(*Target); ok {
+				return guard(i, subShellAction(i))
+			}
 
-				return tea.Sequence(cmd)
+		case key.Matches(msg, t.keyMap.binding["cancel"]):
+			if i, ok := m.SelectedItem().(*Target); ok {
+				i.progress.abort()
+				return nil
 			}
 
 		case key.Matches(msg, t.keyMap.binding["connect"]):
 			if i, ok := m.SelectedItem().(*Target); ok {
-				_, err := i.Connect()
-				if err != nil {
-					return tea.Sequence(func() tea.Msg { return msgError{err: err} })
-				}
-
-				return tea.Sequence(func() tea.Msg { return msgConnect{target: i} })
+				return guard(i, connectAction(i))
 			}
 
 		case key.Matches(msg, t.keyMap.binding["up"]):
@@ -63,7 +63,9 @@ func FavoritesUpdate(t targetDelegate, msg tea.Msg, m *list.Model) tea.Cmd {
 				cmd = m.SetItem(currentIdx, previous)
 				cmds = append(cmds, cmd)
 
-				saveFavoriteList(*m)
+				if err := saveFavoriteList(*m); err != nil {
+					cmds = append(cmds, errorCmd("save favorites", err))
+				}
 				return tea.Sequence(cmds...)
 			}
 
@@ -85,20 +87,24 @@ func FavoritesUpdate(t targetDelegate, msg tea.Msg, m *list.Model) tea.Cmd {
 				cmd = m.SetItem(currentIdx, next)
 				cmds = append(cmds, cmd)
 
-				saveFavoriteList(*m)
+				if err := saveFavoriteList(*m); err != nil {
+					cmds = append(cmds, errorCmd("save favorites", err))
+				}
 				return tea.Sequence(cmds...)
 			}
 
 		case key.Matches(msg, t.keyMap.binding["info"]):
 			if i, ok := m.SelectedItem().(*Target); ok {
-				return tea.Sequence(func() tea.Msg { return msgInfo{target: i} })
+				return tea.Sequence(func() tea.Msg { return msgInfo{text: i.Info()} })
 			}
 		}
 
 	case msgFavorite:
 		cmd := m.InsertItem(len(m.Items()), msg.target)
-		saveFavoriteList(*m)
-		return tea.Sequence(cmd)
+		if err := saveFavoriteList(*m); err != nil {
+			return tea.Sequence(cmd, errorCmd("save favorites", err))
+		}
+		return tea.Sequence(cmd, notifyCmd(levelInfo, "added %s to favorites", msg.target.target.Name))
 
 	case tea.WindowSizeMsg:
 		m.SetSize(msg.Width, msg.Height)
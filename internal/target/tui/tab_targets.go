@@ -16,25 +16,23 @@ func TargetsUpdate(t targetDelegate, msg tea.Msg, m *list.Model) tea.Cmd {
 		switch {
 		case key.Matches(msg, t.keyMap.binding["shell"]):
 			if i, ok := m.SelectedItem().(*Target); ok {
-				cmd, err := i.Shell(func(err error) tea.Msg {
-					return msgError{err: err}
-				})
-				if err != nil {
-					return tea.Sequence(cmd, func() tea.Msg { return msgError{err: err} })
-				}
-
-				return tea.Sequence(cmd)
+				return guard(i, shellAction(i))
 			}
 
-		case key.Matches(msg, t.keyMap.binding["connect"]):
+		case key.Matches(msg, t.keyMap.binding["subshell"]):
 			if i, ok := m.SelectedItem().(*Target); ok {
-				// send connect event upstream
-				_, err := i.Connect()
-				if err != nil {
-					return tea.Sequence(func() tea.Msg { return msgError{err: err} })
-				}
+				return guard(i, subShellAction(i))
+			}
 
-				return tea.Sequence(func() tea.Msg { return msgConnect{target: i} })
+		case key.Matches(msg, t.keyMap.binding["cancel"]):
+			if i, ok := m.SelectedItem().(*Target); ok {
+				i.progress.abort()
+				return nil
+			}
+
+		case key.Matches(msg, t.keyMap.binding["connect"]):
+			if i, ok := m.SelectedItem().(*Target); ok {
+				return guard(i, connectAction(i))
 			}
 
 		case key.Matches(msg, t.keyMap.binding["favorite"]):
@@ -44,7 +42,7 @@ func TargetsUpdate(t targetDelegate, msg tea.Msg, m *list.Model) tea.Cmd {
 
 		case key.Matches(msg, t.keyMap.binding["info"]):
 			if i, ok := m.SelectedItem().(*Target); ok {
-				return tea.Sequence(func() tea.Msg { return msgInfo{target: i} })
+				return tea.Sequence(func() tea.Msg { return msgInfo{text: i.Info()} })
 			}
 
 		case key.Matches(msg, t.keyMap.binding["refresh"]):
@@ -0,0 +1,184 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+
+	"github.com/AndreZiviani/boundary-fuzzy/internal/config"
+	"github.com/AndreZiviani/boundary-fuzzy/internal/keyring"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+const (
+	autoTheme    = "auto"
+	darkTheme    = "dark"
+	lightTheme   = "light"
+	noColorTheme = "no-color"
+)
+
+// Theme holds the colors used by the TUI, any color accepted by lipgloss.Color
+// can be used (e.g. "#7D56F4" or "170")
+type Theme struct {
+	Highlight    string `json:"highlight"`
+	Alert        string `json:"alert"`
+	Muted        string `json:"muted"`
+	Error        string `json:"error"`
+	Warning      string `json:"warning"`
+	Text         string `json:"text"`
+	Selected     string `json:"selected"`
+	SelectedDesc string `json:"selected_desc"`
+	Dimmed       string `json:"dimmed"`
+}
+
+var themes = map[string]Theme{
+	darkTheme: {
+		Highlight:    "#7D56F4",
+		Alert:        "170",
+		Muted:        "241",
+		Error:        "#cc0000",
+		Warning:      "#d7af00",
+		Text:         "#dddddd",
+		Selected:     "#EE6FF8",
+		SelectedDesc: "#AD58B4",
+		Dimmed:       "#777777",
+	},
+	lightTheme: {
+		Highlight:    "#874BFD",
+		Alert:        "127",
+		Muted:        "243",
+		Error:        "#b00000",
+		Warning:      "#af8700",
+		Text:         "#1a1a1a",
+		Selected:     "#A020A0",
+		SelectedDesc: "#C44AC8",
+		Dimmed:       "#8a8a8a",
+	},
+	"high-contrast": {
+		Highlight:    "15",
+		Alert:        "11",
+		Muted:        "15",
+		Error:        "9",
+		Warning:      "11",
+		Text:         "15",
+		Selected:     "11",
+		SelectedDesc: "11",
+		Dimmed:       "7",
+	},
+	noColorTheme: {},
+}
+
+// environment colors the window border when the selected target matches a pattern
+type environment struct {
+	pattern *regexp.Regexp
+	color   lipgloss.Color
+}
+
+var environments []environment
+
+// loadTheme resolves the configured theme, applies the optional theme file on
+// top of it and updates every style used by the TUI
+func loadTheme(settings config.Settings) error {
+	name := settings.Theme
+	// https://no-color.org, an empty NO_COLOR does not disable colors
+	if os.Getenv("NO_COLOR") != "" {
+		name = noColorTheme
+	}
+
+	if name == autoTheme {
+		name = lightTheme
+		if lipgloss.HasDarkBackground() {
+			name = darkTheme
+		}
+	}
+
+	theme, ok := themes[name]
+	if !ok {
+		return fmt.Errorf("unknown theme %q", name)
+	}
+
+	if settings.ThemeFile != "" && name != noColorTheme {
+		data, err := os.ReadFile(settings.ThemeFile)
+		if err != nil {
+			return err
+		}
+
+		// colors missing from the file are kept from the base theme
+		if err := json.Unmarshal(data, &theme); err != nil {
+			return fmt.Errorf("could not parse %s: %w", settings.ThemeFile, err)
+		}
+	}
+
+	environments = nil
+	for _, pattern := range slices.Sorted(maps.Keys(settings.EnvironmentColors)) {
+		color := settings.EnvironmentColors[pattern]
+		if color == "" {
+			continue
+		}
+
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return fmt.Errorf("invalid environment pattern %q: %w", pattern, err)
+		}
+		environments = append(environments, environment{pattern: re, color: lipgloss.Color(color)})
+	}
+
+	if name == noColorTheme {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+
+	applyTheme(theme)
+	return nil
+}
+
+func applyTheme(theme Theme) {
+	highlight = lipgloss.Color(theme.Highlight)
+
+	alertViewStyle = alertViewStyle.BorderForeground(lipgloss.Color(theme.Alert))
+	choiceStyle = choiceStyle.Foreground(lipgloss.Color(theme.Muted))
+	errorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Error)).Render
+	warningStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Warning)).Render
+	bannerStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(theme.Error)).Render
+	helpTitleStyle = helpTitleStyle.Foreground(highlight)
+	helpKeyStyle = helpKeyStyle.Foreground(lipgloss.Color(theme.Muted))
+	windowStyle = windowStyle.BorderForeground(highlight)
+	tabStyle = tabStyle.BorderForeground(highlight)
+	activeTab = tabStyle.Border(activeTabBorder, true)
+
+	itemStyles = list.NewDefaultItemStyles()
+	itemStyles.NormalTitle = itemStyles.NormalTitle.Foreground(lipgloss.Color(theme.Text))
+	itemStyles.NormalDesc = itemStyles.NormalDesc.Foreground(lipgloss.Color(theme.Dimmed))
+	itemStyles.SelectedTitle = itemStyles.SelectedTitle.Foreground(lipgloss.Color(theme.Selected)).BorderForeground(lipgloss.Color(theme.SelectedDesc))
+	itemStyles.SelectedDesc = itemStyles.SelectedDesc.Foreground(lipgloss.Color(theme.SelectedDesc)).BorderForeground(lipgloss.Color(theme.SelectedDesc))
+	itemStyles.DimmedTitle = itemStyles.DimmedTitle.Foreground(lipgloss.Color(theme.Dimmed))
+	itemStyles.DimmedDesc = itemStyles.DimmedDesc.Foreground(lipgloss.Color(theme.Muted))
+}
+
+// environmentColor returns the border color for a target, or the highlight
+// color if neither its scope, its name nor the profile, the name of the token
+// in use, match an environment
+func environmentColor(target *Target) lipgloss.TerminalColor {
+	if target == nil {
+		return highlight
+	}
+
+	names := []string{target.target.Name, keyring.TokenName()}
+	if target.target.Scope != nil {
+		names = append(names, target.target.Scope.Name)
+	}
+
+	for _, env := range environments {
+		for _, name := range names {
+			if env.pattern.MatchString(name) {
+				return env.color
+			}
+		}
+	}
+
+	return highlight
+}
@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/AndreZiviani/boundary-fuzzy/internal/config"
+	"github.com/AndreZiviani/boundary-fuzzy/internal/keyring"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hashicorp/boundary/api/scopes"
+	"github.com/hashicorp/boundary/api/targets"
+)
+
+func TestEnvironmentColor(t *testing.T) {
+	settings := config.DefaultSettings()
+	settings.Theme = darkTheme
+	settings.EnvironmentColors = map[string]string{"prod": "#cc0000", "^staging$": "#cccc00"}
+	if err := loadTheme(settings); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { loadTheme(config.DefaultSettings()) })
+
+	tests := []struct {
+		name      string
+		target    string
+		scope     string
+		tokenName string
+		want      lipgloss.TerminalColor
+	}{
+		{name: "scope", target: "postgres", scope: "Prod", want: lipgloss.Color("#cc0000")},
+		{name: "target name only", target: "postgres-prod", scope: "databases", want: lipgloss.Color("#cc0000")},
+		{name: "profile", target: "postgres", scope: "databases", tokenName: "prod", want: lipgloss.Color("#cc0000")},
+		{name: "anchored pattern", target: "postgres", scope: "staging", want: lipgloss.Color("#cccc00")},
+		{name: "no match", target: "postgres", scope: "staging-eu", want: highlight},
+		{name: "no scope", target: "redis", want: highlight},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(keyring.EnvTokenName, tt.tokenName)
+
+			target := &Target{target: &targets.Target{Name: tt.target}}
+			if tt.scope != "" {
+				target.target.Scope = &scopes.ScopeInfo{Name: tt.scope}
+			}
+
+			if got := environmentColor(target); got != tt.want {
+				t.Fatalf("environmentColor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
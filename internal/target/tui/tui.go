@@ -4,8 +4,14 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/AndreZiviani/boundary-fuzzy/internal/config"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/hashicorp/boundary/api"
@@ -25,16 +31,46 @@ type tui struct {
 	targetKeyMap    *DelegateKeyMap
 	connectedKeyMap *DelegateKeyMap
 	favoriteKeyMap  *DelegateKeyMap
+	keyBindings     keyBindings
 
 	boundaryClient *api.Client
 	targetsClient  *targets.Client
 	sessionsClient *sessions.Client
 	boundaryToken  *authtokens.AuthToken
+	config         config.Config
+	sessions       *sessionManager
 
 	width      int
 	height     int
 	shouldQuit bool
 	message    string
+
+	pending      *msgConfirm
+	confirmInput textinput.Model
+	connected    *msgConnected
+	// deferred holds the messages that open a view while another one is
+	// open, they are handled once the user is back at the tabs
+	deferred []tea.Msg
+
+	logSession  *SessionInfo
+	logViewport viewport.Model
+	logTick     int
+
+	notifications   []notification
+	toast           *notification
+	toastId         int
+	historyViewport viewport.Model
+
+	spinner    spinner.Model
+	spinning   bool
+	refreshing bool
+	// cachedAt is when the targets shown were cached, it is zero once they
+	// are refreshed
+	cachedAt time.Time
+
+	// loginPrompted is the id of the token the user was asked to renew
+	loginPrompted string
+	loggingIn     bool
 }
 
 const (
@@ -42,45 +78,124 @@ const (
 	connectedView
 	favoriteView
 	messageView
-	errorView
 	quittingView
+	helpView
+	confirmView
+	connectionView
+	logView
+	notificationsView
+	loginView
 )
 
 func (t tui) Init() tea.Cmd {
-	return nil
+	if !t.cachedAt.IsZero() {
+		return tea.Batch(tokenTickCmd(), func() tea.Msg { return msgRefresh{} })
+	}
+
+	return tokenTickCmd()
 }
 
 func (t tui) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch t.state {
-	case errorView, messageView:
-		return t.messageUpdate(msg)
-	case quittingView:
-		return t.quittingUpdate(msg)
-	}
-
+	// notifications and background work are handled whatever the current view is
 	switch msg := msg.(type) {
-	case tea.WindowSizeMsg:
-		t.width = msg.Width
-		t.height = msg.Height
+	case msgBusy:
+		return t, t.startSpinner()
+
+	case spinner.TickMsg:
+		if !t.busy() {
+			t.spinning = false
+			return t, nil
+		}
+
+		var cmd tea.Cmd
+		t.spinner, cmd = t.spinner.Update(msg)
+		spinnerFrame = t.spinner.View()
+		return t, cmd
+
+	case msgConnectResult:
+		return t.connectResult(msg)
+
+	case msgSessionChanged:
+		return t.sessionChanged(msg)
+
+	case msgConnectionsLeft:
+		return t.connectionsLeft(msg)
+
+	case msgTokenTick:
+		return t.tokenTick()
+
+	case msgLogin:
+		return t.login(msg)
+
+	case msgTargets:
+		before := t.tabs[targetsView].Items()
+		if err := t.setTargets(msg); err != nil {
+			return t, errorCmd("refresh targets", err)
+		}
+		after := t.tabs[targetsView].Items()
+		return t, notifyCmd(levelInfo, "loaded %d targets%s", len(after), targetChanges(before, after))
+
+	case msgError:
+		if isUnauthenticated(msg.err) {
+			return t.reauthenticate(msg.err)
+		}
+		return t.notify(msgNotify{level: levelError, text: msg.err.Error()})
+
+	case msgNotify:
+		return t.notify(msg)
 
-		// remove tabs and borders from the window size message
-		msg.Height -= 4
-		msg.Width -= 2
+	case msgClearToast:
+		if msg.id == t.toastId {
+			t.toast = nil
+		}
+		return t, nil
 
+	case msgConnect:
+		// the connected tab is updated even while a view is open
 		return t, t.UpdateTabs(msg)
+
+	case msgRefresh:
+		if t.refreshing {
+			return t, nil
+		}
+		return t, t.refreshTargetsCmd()
+	}
+
+	if !t.inTabs() {
+		switch msg.(type) {
+		case msgConnected, msgLogs, msgInfo, msgConfirm:
+			t.deferred = append(t.deferred, msg)
+			return t, nil
+		}
+
+		model, cmd := t.viewUpdate(msg)
+		return model.(tui).resumeDeferred(cmd)
+	}
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		return t.resize(msg)
 	case tea.KeyMsg:
 		// Don't match any of the keys below if we're actively filtering.
 		if t.InFilterState() {
 			break
 		}
 
-		switch msg.String() {
-		case "q", "ctrl+c":
+		switch {
+		case key.Matches(msg, t.keyBindings.get(globalBindings, "quit")):
 			return t.gracefullyQuit(msg)
 
-		case "tab":
+		case key.Matches(msg, t.keyBindings.get(globalBindings, "next_tab")):
 			t.GoNextTab()
 			return t, func() tea.Msg { return tea.ClearScreen() }
+
+		case key.Matches(msg, t.keyBindings.get(globalBindings, "help")):
+			t.SetState(helpView)
+			return t, nil
+
+		case key.Matches(msg, t.keyBindings.get(globalBindings, "notifications")):
+			return t.startNotifications()
+
 		default:
 			// only send custom messages to the current tab
 			m, cmd := t.CurrentTab().Update(msg)
@@ -88,12 +203,19 @@ func (t tui) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return t, cmd
 		}
 
-	case msgError:
-		t.SetStateAndMessage(errorView, msg.err.Error())
+	case msgConfirm:
+		return t.startConfirm(msg)
+
+	case msgConnected:
+		t.connected = &msg
+		t.SetState(connectionView)
 		return t, nil
 
+	case msgLogs:
+		return t.startLogs(msg)
+
 	case msgInfo:
-		t.SetStateAndMessage(messageView, msg.target.Info())
+		t.SetStateAndMessage(messageView, msg.text)
 		return t, nil
 
 	default:
@@ -105,17 +227,70 @@ func (t tui) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return t, nil
 }
 
+// viewUpdate handles the messages of the view open on top of the tabs
+func (t tui) viewUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch t.state {
+	case messageView, helpView:
+		return t.messageUpdate(msg)
+	case quittingView:
+		return t.quittingUpdate(msg)
+	case confirmView:
+		return t.confirmUpdate(msg)
+	case connectionView:
+		return t.connectedUpdate(msg)
+	case logView:
+		return t.logUpdate(msg)
+	case notificationsView:
+		return t.notificationsUpdate(msg)
+	case loginView:
+		return t.loginUpdate(msg)
+	}
+
+	return t, nil
+}
+
+// resumeDeferred handles the deferred messages once the view was closed
+func (t tui) resumeDeferred(cmd tea.Cmd) (tea.Model, tea.Cmd) {
+	if !t.inTabs() || len(t.deferred) == 0 {
+		return t, cmd
+	}
+
+	cmds := []tea.Cmd{cmd}
+	for _, msg := range t.deferred {
+		cmds = append(cmds, func() tea.Msg { return msg })
+	}
+	t.deferred = nil
+
+	// a deferred message that opens a view defers the ones after it again
+	return t, tea.Sequence(cmds...)
+}
+
 func (t tui) View() string {
 	switch t.state {
 	case messageView:
 		return t.HandleMessageView()
 
-	case errorView:
-		return t.HandleErrorView()
-
 	case quittingView:
 		return t.HandleQuittingView()
 
+	case helpView:
+		return t.HandleHelpView()
+
+	case confirmView:
+		return t.HandleConfirmView()
+
+	case connectionView:
+		return t.HandleConnectedView()
+
+	case logView:
+		return t.HandleLogView()
+
+	case notificationsView:
+		return t.HandleNotificationsView()
+
+	case loginView:
+		return t.HandleLoginView()
+
 	default:
 		return t.HandleDefaultView()
 
@@ -127,8 +302,21 @@ func (t *tui) HandleDefaultView() string {
 		return ""
 	}
 
-	renderedTabs := t.RenderTabs()
+	// color borders according to the environment of the selected target
+	border := highlight
+	if target := itemTarget(t.CurrentTab().SelectedItem()); target != nil {
+		border = environmentColor(target)
+	}
+
+	return t.renderWindow(t.state, border, t.CurrentTab().View())
+}
 
+// RenderPane renders a titled pane in place of the tab content
+func (t *tui) RenderPane(active sessionState, border lipgloss.TerminalColor, title, content, footer string) string {
+	return t.renderWindow(active, border, lipgloss.JoinVertical(lipgloss.Left, helpTitleStyle.Render(title), content, footer))
+}
+
+func (t *tui) renderWindow(active sessionState, border lipgloss.TerminalColor, content string) string {
 	style := lipgloss.NewStyle().
 		MaxHeight(t.height).
 		MaxWidth(t.width)
@@ -136,23 +324,39 @@ func (t *tui) HandleDefaultView() string {
 	return style.Render(
 		lipgloss.JoinVertical(
 			lipgloss.Top,
-			renderedTabs,
+			t.RenderTabs(active, border),
 			windowStyle.
+				BorderForeground(border).
 				// force width here to make sure border is rendered correctly
 				Width(t.width-2).
-				Render(
-					t.CurrentTab().View(),
-				),
+				Render(content),
+			t.RenderStatusBar(),
 		),
 	)
 }
 
-func (t *tui) RenderTabs() string {
+// resize keeps the window size and resizes the tabs, the views open on top of
+// them must handle it too or the tabs keep the old size once they are closed
+func (t tui) resize(msg tea.WindowSizeMsg) (tea.Model, tea.Cmd) {
+	t.width = msg.Width
+	t.height = msg.Height
+
+	return t, t.UpdateTabs(t.tabsSize(msg))
+}
+
+// tabsSize removes the tabs, borders and status bar from the window size
+func (t *tui) tabsSize(msg tea.WindowSizeMsg) tea.WindowSizeMsg {
+	msg.Height -= 5
+	msg.Width -= 2
+	return msg
+}
+
+func (t *tui) RenderTabs(active sessionState, borderColor lipgloss.TerminalColor) string {
 	out := []string{}
 	for i := range t.tabs {
 		isFirst := i == 0
 		isLast := i == len(t.tabs)-1
-		isActive := i == int(t.state)
+		isActive := i == int(active)
 
 		style := tabStyle
 		if isActive {
@@ -169,16 +373,23 @@ func (t *tui) RenderTabs() string {
 			border.BottomRight = "┴"
 		}
 
-		out = append(out, style.Border(border).Render(
+		out = append(out, style.Border(border).BorderForeground(borderColor).Render(
 			fmt.Sprintf("%s (%d)", t.tabs[i].Title, len(t.tabs[i].Items())),
 		))
 	}
 
 	row := lipgloss.JoinHorizontal(lipgloss.Top, out...)
 
-	gap := lipgloss.NewStyle().Foreground(highlight).Render(
+	gapWidth := max(0, t.width-lipgloss.Width(row)-1)
+	gap := lipgloss.NewStyle().Foreground(borderColor).Render(
 		// Create a gap with the same width as the row, but with the tab border on the right
-		strings.Repeat(tabBorder.Top, max(0, t.width-lipgloss.Width(row)-1)) + tabBorder.TopRight)
+		strings.Repeat(tabBorder.Top, gapWidth) + tabBorder.TopRight)
+
+	// show the token countdown above the gap, aligned to the right
+	if countdown := t.tokenCountdown(); countdown != "" && lipgloss.Width(countdown)+1 < gapWidth {
+		header := lipgloss.NewStyle().Width(gapWidth + 1).Align(lipgloss.Right).Render(countdown + " ")
+		gap = lipgloss.JoinVertical(lipgloss.Right, header, gap)
+	}
 
 	row = lipgloss.JoinHorizontal(lipgloss.Bottom, row, gap)
 	return row
@@ -0,0 +1,107 @@
+package tui
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AndreZiviani/boundary-fuzzy/internal/config"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hashicorp/boundary/api"
+	"github.com/hashicorp/boundary/api/targets"
+)
+
+func newTestTui(t *testing.T) tui {
+	t.Helper()
+
+	settings := config.DefaultSettings()
+	bindings, err := newKeyBindings(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetList, targetKeyMap := NewList(targetsTabName, targetsView, []list.Item{}, bindings[targetsBindings], TargetsUpdate, nil)
+	connectedList, connectedKeyMap := NewList(connectedTabName, connectedView, []list.Item{}, bindings[connectedBindings], ConnectedUpdate, nil)
+	favoriteList, favoriteKeyMap := NewList(favoritesTabName, favoriteView, []list.Item{}, bindings[favoriteBindings], FavoritesUpdate, nil)
+
+	boundaryClient, err := api.NewClient(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return newTui(context.Background(), TuiInput{
+		BoundaryClient:  boundaryClient,
+		Config:          config.Config{Settings: settings},
+		KeyBindings:     bindings,
+		Tabs:            []*list.Model{&targetList, &connectedList, &favoriteList},
+		TargetKeyMap:    targetKeyMap,
+		ConnectedKeyMap: connectedKeyMap,
+		FavoriteKeyMap:  favoriteKeyMap,
+	})
+}
+
+func newTestSession(id string) *SessionInfo {
+	return &SessionInfo{target: &Target{target: &targets.Target{Id: id, Name: id}, progress: &progress{}}}
+}
+
+// update sends msg and then the messages produced by its commands
+func update(model tea.Model, msg tea.Msg) tea.Model {
+	model, cmd := model.Update(msg)
+	for _, next := range runCmd(cmd) {
+		model = update(model, next)
+	}
+
+	return model
+}
+
+func TestConnectWhileViewIsOpen(t *testing.T) {
+	m := newTestTui(t)
+	m.SetStateAndMessage(messageView, "info")
+
+	session := newTestSession("ttcp_1")
+	model := update(m, msgConnect{target: session.target, session: session})
+
+	items := model.(tui).tabs[connectedView].Items()
+	if len(items) != 1 || items[0] != list.Item(session) {
+		t.Fatalf("connected tab has %v, want the new session", items)
+	}
+}
+
+func TestDeferredUntilViewCloses(t *testing.T) {
+	m := newTestTui(t)
+	m.SetStateAndMessage(messageView, "info")
+
+	session := newTestSession("ttcp_1")
+	model := update(m, msgConnected{session: session, url: "http://127.0.0.1:1234"})
+	if state := model.(tui).state; state != messageView {
+		t.Fatalf("state is %d, the open view should stay open", state)
+	}
+
+	// closing the message shows the deferred connection
+	model = update(model, tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(tui)
+	if m.state != connectionView || m.connected == nil || m.connected.session != session {
+		t.Fatalf("state is %d, want the connection view of the deferred message", m.state)
+	}
+	if len(m.deferred) != 0 {
+		t.Fatalf("%d messages still deferred", len(m.deferred))
+	}
+}
+
+func TestWindowSizeWhileViewIsOpen(t *testing.T) {
+	for _, state := range []sessionState{messageView, quittingView, confirmView, connectionView, loginView} {
+		m := newTestTui(t)
+		m.SetState(state)
+
+		m = update(m, tea.WindowSizeMsg{Width: 120, Height: 40}).(tui)
+		if m.width != 120 || m.height != 40 {
+			t.Fatalf("view %d: window is %dx%d, want 120x40", state, m.width, m.height)
+		}
+		if width := m.tabs[targetsView].Width(); width != 118 {
+			t.Fatalf("view %d: tabs are %d wide, want 118", state, width)
+		}
+		if m.state != state {
+			t.Fatalf("view %d: state changed to %d", state, m.state)
+		}
+	}
+}
@@ -19,6 +19,16 @@ func (t *tui) InFilterState() bool {
 	return t.CurrentTab().FilterState() == list.Filtering
 }
 
+// inTabs reports if one of the tabs is shown, no other view is open
+func (t *tui) inTabs() bool {
+	switch t.state {
+	case targetsView, connectedView, favoriteView:
+		return true
+	}
+
+	return false
+}
+
 func (t *tui) CurrentTab() *list.Model {
 	return t.tabs[t.state]
 }
@@ -62,12 +72,8 @@ func (t *tui) UpdateTabs(msg tea.Msg) tea.Cmd {
 	return tea.Batch(cmds...)
 }
 func (t *tui) terminateAllSessions() {
-	for _, item := range t.tabs[connectedView].Items() {
-		target := item.(*Target)
-		if target.session == nil {
-			target.session.Terminate(target.task)
-		}
-	}
+	// we are quitting, there is nowhere to report the sessions left behind
+	_ = t.sessions.TerminateAll()
 }
 
 func (t *tui) saveFavoriteList(list *list.Model) error {